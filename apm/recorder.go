@@ -1,26 +1,169 @@
-// Package apm mimics a real trace recorder integration.
+// Package apm is a trace recorder integration with Elastic APM.
 package apm
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
 
 	"go.elastic.co/apm/v2"
+	"go.elastic.co/apm/v2/transport"
 
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
 	"github.com/silvan-talos/tlp/transaction"
 )
 
 // Recorder is the implementation of an APM trace recorder.
+// Transactions and spans are reported when they end, with their attrs mapped to APM labels.
 type Recorder struct {
-	// create specific APM tracer instead of using the default
+	tracer *apm.Tracer
+	// sampleErrors marks the transactions recording an error as sampled.
+	sampleErrors bool
 }
 
-func NewRecorder() *Recorder {
-	return &Recorder{}
+// NewRecorder creates a recorder with its own APM tracer, configured from cfg.
+// Settings missing from cfg fall back to the ELASTIC_APM_* environment variables.
+func NewRecorder(cfg config.TransactionConfig) (*Recorder, error) {
+	opts := apm.TracerOptions{
+		ServiceName:        cfg.ServiceName,
+		ServiceVersion:     cfg.ServiceVersion,
+		ServiceEnvironment: cfg.Environment,
+	}
+	if cfg.ServerURL != "" {
+		serverURL, err := url.Parse(cfg.ServerURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse server url: %w", err)
+		}
+		opts.Transport, err = transport.NewHTTPTransport(transport.HTTPTransportOptions{
+			ServerURLs: []*url.URL{serverURL},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create transport: %w", err)
+		}
+	}
+	tracer, err := apm.NewTracerOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("create tracer: %w", err)
+	}
+	if cfg.SampleRate != nil {
+		tracer.SetSampler(apm.NewRatioSampler(*cfg.SampleRate))
+	}
+	return &Recorder{
+		tracer:       tracer,
+		sampleErrors: cfg.SampleErrors,
+	}, nil
+}
+
+// Close sends the buffered events to the APM server and stops the tracer.
+func (r *Recorder) Close() {
+	r.tracer.Flush(nil)
+	r.tracer.Close()
 }
 
 func (r *Recorder) RecordTransaction(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context) {
-	tx := apm.DefaultTracer().StartTransaction(name, transactionType)
-	return &transaction.Transaction{
-		TraceID: tx.TraceContext().Trace.String(),
+	var opts apm.TransactionOptions
+	parent, remote := transaction.TraceParentFromContext(ctx)
+	if remote {
+		opts.TraceContext = traceContext(parent)
+	}
+	tx := r.tracer.StartTransactionOptions(name, transactionType, opts)
+	traceCtx := tx.TraceContext()
+	result := &transaction.Transaction{
+		TraceID:    traceCtx.Trace.String(),
+		ID:         traceCtx.Span.String(),
+		TraceState: traceCtx.State.String(),
+	}
+	result.SetSampled(traceCtx.Options.Recorded())
+	if remote {
+		result.ParentID = parent.ParentID
+	}
+	return result, apm.ContextWithTransaction(ctx, tx)
+}
+
+func (r *Recorder) RecordSpan(ctx context.Context, tx *transaction.Transaction, name, spanType string) (*transaction.Span, context.Context) {
+	span, ctx := apm.StartSpan(ctx, name, spanType)
+	return &transaction.Span{
+		ID: span.TraceContext().Span.String(),
 	}, ctx
 }
+
+// EndTransaction sets the transaction labels, result and outcome and reports it to APM.
+func (r *Recorder) EndTransaction(ctx context.Context, tx *transaction.Transaction) {
+	apmTx := apm.TransactionFromContext(ctx)
+	if apmTx == nil {
+		return
+	}
+	setLabels(&apmTx.Context, tx.GetAttrs())
+	apmTx.Result = tx.Result()
+	if outcome := tx.Outcome(); outcome != transaction.OutcomeUnknown {
+		apmTx.Outcome = string(outcome)
+	}
+	apmTx.Duration = tx.GetDuration()
+	apmTx.End()
+}
+
+func (r *Recorder) EndSpan(ctx context.Context, span *transaction.Span) {
+	apmSpan := apm.SpanFromContext(ctx)
+	if apmSpan == nil {
+		return
+	}
+	setLabels(&apmSpan.Context, span.Attrs)
+	apmSpan.Duration = span.GetDuration()
+	apmSpan.End()
+}
+
+// RecordError reports the error to APM, linked to the transaction, and marks the APM transaction as failed.
+// With cfg.SampleErrors, the transaction becomes sampled, so its next entries are logged and the flag is propagated
+// to the downstream services. The sampling decision of the APM transaction itself is made when it starts.
+func (r *Recorder) RecordError(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error) {
+	if r.sampleErrors {
+		tx.SetSampled(true)
+	}
+	if apmTx := apm.TransactionFromContext(ctx); apmTx != nil {
+		apmTx.Outcome = string(transaction.OutcomeFailure)
+	}
+	e := apm.CaptureError(ctx, txErr.Err)
+	if e == nil {
+		return
+	}
+	e.Timestamp = txErr.Time
+	e.Send()
+}
+
+// traceContext converts a W3C trace parent into an APM trace context.
+// An invalid trace parent results in a zero trace context, which starts a new trace.
+func traceContext(parent transaction.TraceParent) apm.TraceContext {
+	var traceCtx apm.TraceContext
+	if _, err := hex.Decode(traceCtx.Trace[:], []byte(parent.TraceID)); err != nil {
+		return apm.TraceContext{}
+	}
+	if _, err := hex.Decode(traceCtx.Span[:], []byte(parent.ParentID)); err != nil {
+		return apm.TraceContext{}
+	}
+	traceCtx.Options = traceCtx.Options.WithRecorded(parent.Sampled)
+	var entries []apm.TraceStateEntry
+	for _, member := range strings.Split(parent.State, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok {
+			continue
+		}
+		entries = append(entries, apm.TraceStateEntry{Key: key, Value: value})
+	}
+	traceCtx.State = apm.NewTraceState(entries...)
+	return traceCtx
+}
+
+// labeler is implemented by both APM transaction and span contexts.
+type labeler interface {
+	SetLabel(key string, value interface{})
+}
+
+// setLabels sets the attrs as labels, flattening groups into dotted keys.
+func setLabels(l labeler, attrs []logging.Attr) {
+	for _, attr := range logging.Flatten(logging.ResolveAttrs(attrs)) {
+		l.SetLabel(attr.Key, attr.Value.Any())
+	}
+}
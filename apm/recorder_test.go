@@ -0,0 +1,123 @@
+package apm_test
+
+import (
+	"bufio"
+	"compress/zlib"
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/apm"
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+// fakeServer is a minimal APM server collecting the reported events by type.
+type fakeServer struct {
+	mu     sync.Mutex
+	events map[string][]map[string]any
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/intake/v2/events" {
+		_, _ = w.Write([]byte(`{"version":"8.0.0"}`))
+		return
+	}
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "deflate" {
+		zr, err := zlib.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer zr.Close()
+		body = zr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var event map[string]map[string]any
+		if err := stdjson.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		for eventType, payload := range event {
+			s.events[eventType] = append(s.events[eventType], payload)
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *fakeServer) get(eventType string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events[eventType]
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	server := &fakeServer{events: make(map[string][]map[string]any)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	recorder, err := apm.NewRecorder(config.TransactionConfig{
+		ServiceName:    "tlp-test",
+		ServiceVersion: "1.0.0",
+		Environment:    "test",
+		ServerURL:      ts.URL,
+	})
+	require.NoError(t, err, "recorder should be created")
+	tracer := transaction.NewTracer(recorder)
+
+	tx, ctx := tracer.StartTransaction(context.Background(), "GET /users/:id", "request", logging.NewAttr("userID", "12"))
+	span, _ := transaction.StartSpan(ctx, "select user", "db", logging.NewAttr("table", "users"))
+	span.End()
+	tx.RecordError(errors.New("user not found"))
+	tx.SetResult("HTTP 4xx")
+	tx.End()
+	recorder.Close()
+
+	transactions := server.get("transaction")
+	require.Len(t, transactions, 1, "transaction should be reported")
+	apmTx := transactions[0]
+	require.Equal(t, "GET /users/:id", apmTx["name"])
+	require.Equal(t, tx.TraceID, apmTx["trace_id"], "trace ID should match")
+	require.Equal(t, tx.ID, apmTx["id"], "transaction ID should match")
+	require.Equal(t, "HTTP 4xx", apmTx["result"])
+	require.Equal(t, "failure", apmTx["outcome"])
+	require.Equal(t, "12", apmTx["context"].(map[string]any)["tags"].(map[string]any)["userID"], "attrs should be mapped to labels")
+
+	spans := server.get("span")
+	require.Len(t, spans, 1, "span should be reported")
+	require.Equal(t, span.ID, spans[0]["id"], "span ID should match")
+	require.Equal(t, tx.ID, spans[0]["parent_id"], "span should be a child of the transaction")
+	require.Equal(t, "users", spans[0]["context"].(map[string]any)["tags"].(map[string]any)["table"])
+
+	apmErrors := server.get("error")
+	require.Len(t, apmErrors, 1, "error should be reported")
+	require.Equal(t, tx.ID, apmErrors[0]["transaction_id"], "error should be linked to the transaction")
+
+	metadata := server.get("metadata")
+	require.NotEmpty(t, metadata, "metadata should be reported")
+	service := metadata[0]["service"].(map[string]any)
+	require.Equal(t, "tlp-test", service["name"])
+	require.Equal(t, "1.0.0", service["version"])
+	require.Equal(t, "test", service["environment"])
+}
+
+func TestNewRecorder_InvalidServerURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := apm.NewRecorder(config.TransactionConfig{ServerURL: "://invalid"})
+	require.Error(t, err, "invalid server url should be rejected")
+}
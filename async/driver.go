@@ -0,0 +1,210 @@
+// Package async provides a non-blocking driver wrapper, which hands entries over to a background writer.
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+const defaultQueueSize = 1024
+
+// OverflowPolicy decides what happens to new entries when the queue is full.
+type OverflowPolicy int
+
+const (
+	// Block waits for the writer to free a slot.
+	Block OverflowPolicy = iota
+	// DropNewest discards the new entry.
+	DropNewest
+	// DropOldest discards the oldest queued entry to make room for the new one.
+	DropOldest
+	// DropBelowLevel discards new entries below Options.MinLevel and blocks for the others.
+	DropBelowLevel
+)
+
+type Options struct {
+	// QueueSize is the number of entries buffered before the overflow policy applies. Defaults to 1024.
+	QueueSize int
+	Overflow  OverflowPolicy
+	// MinLevel is the lowest level kept by the DropBelowLevel policy when the queue is full.
+	MinLevel logging.Level
+}
+
+// driver is the log.Driver interface, declared here to avoid depending on the log package.
+type driver interface {
+	Log(ctx context.Context, entry logging.Entry)
+}
+
+type item struct {
+	ctx   context.Context
+	entry logging.Entry
+}
+
+// Driver queues entries in a bounded ring buffer and logs them to the wrapped driver from a background goroutine.
+type Driver struct {
+	next     driver
+	overflow OverflowPolicy
+	minLevel logging.Level
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	queue    []item
+	head     int
+	count    int
+	closed   bool
+	// enqueued and done count the accepted entries and the ones written or dropped after being queued.
+	enqueued uint64
+	done     uint64
+	// progress is closed and replaced whenever done changes while someone is flushing.
+	progress chan struct{}
+	flushing int
+	stopped  chan struct{}
+
+	dropped atomic.Uint64
+}
+
+// NewDriver wraps next and starts the background writer. Close must be called to release it.
+func NewDriver(next driver, opts Options) *Driver {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	d := &Driver{
+		next:     next,
+		overflow: opts.Overflow,
+		minLevel: opts.MinLevel,
+		queue:    make([]item, opts.QueueSize),
+		progress: make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	d.notEmpty = sync.NewCond(&d.mu)
+	d.notFull = sync.NewCond(&d.mu)
+	go d.run()
+	return d
+}
+
+// Log queues the entry. The attribute slices are copied, since their owners may change them after Log returns.
+// Entries logged after Close are dropped.
+func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
+	it := item{ctx: context.WithoutCancel(ctx), entry: entry.Clone()}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for !d.closed && d.count == len(d.queue) {
+		switch {
+		case d.overflow == DropNewest,
+			d.overflow == DropBelowLevel && entry.Level < d.minLevel:
+			d.dropped.Add(1)
+			return
+		case d.overflow == DropOldest:
+			d.pop()
+			d.dropped.Add(1)
+			d.markDone()
+		default:
+			d.notFull.Wait()
+		}
+	}
+	if d.closed {
+		d.dropped.Add(1)
+		return
+	}
+	d.queue[(d.head+d.count)%len(d.queue)] = it
+	d.count++
+	d.enqueued++
+	d.notEmpty.Signal()
+}
+
+// Dropped returns the number of entries discarded so far.
+func (d *Driver) Dropped() uint64 {
+	return d.dropped.Load()
+}
+
+// Flush waits until the entries queued before the call are written, then flushes the wrapped driver
+// if it supports flushing.
+func (d *Driver) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	target := d.enqueued
+	d.flushing++
+	for d.done < target {
+		progress := d.progress
+		d.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			d.mu.Lock()
+			d.flushing--
+			d.mu.Unlock()
+			return ctx.Err()
+		case <-progress:
+		}
+		d.mu.Lock()
+	}
+	d.flushing--
+	d.mu.Unlock()
+	if f, ok := d.next.(interface{ Flush(context.Context) error }); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// Close stops accepting entries and waits for the queued ones to be written. The wrapped driver is closed
+// as well if it supports closing.
+func (d *Driver) Close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.notEmpty.Broadcast()
+	d.notFull.Broadcast()
+	d.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.stopped:
+	}
+	if c, ok := d.next.(interface{ Close(context.Context) error }); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+func (d *Driver) run() {
+	defer close(d.stopped)
+	for {
+		d.mu.Lock()
+		for d.count == 0 && !d.closed {
+			d.notEmpty.Wait()
+		}
+		if d.count == 0 {
+			d.mu.Unlock()
+			return
+		}
+		it := d.pop()
+		d.notFull.Signal()
+		d.mu.Unlock()
+
+		d.next.Log(it.ctx, it.entry)
+
+		d.mu.Lock()
+		d.markDone()
+		d.mu.Unlock()
+	}
+}
+
+// pop removes the oldest queued item. The caller must hold d.mu.
+func (d *Driver) pop() item {
+	it := d.queue[d.head]
+	d.queue[d.head] = item{}
+	d.head = (d.head + 1) % len(d.queue)
+	d.count--
+	return it
+}
+
+// markDone records the completion of a queued entry and wakes up the flushing callers. The caller must hold d.mu.
+func (d *Driver) markDone() {
+	d.done++
+	if d.flushing > 0 {
+		close(d.progress)
+		d.progress = make(chan struct{})
+	}
+}
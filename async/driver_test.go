@@ -0,0 +1,171 @@
+package async_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/async"
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// gatedDriver blocks every Log call until the gate is opened and records the logged messages.
+type gatedDriver struct {
+	gate    chan struct{}
+	started chan struct{}
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newGatedDriver() *gatedDriver {
+	return &gatedDriver{
+		gate:    make(chan struct{}),
+		started: make(chan struct{}, 100),
+	}
+}
+
+func (d *gatedDriver) Log(ctx context.Context, entry logging.Entry) {
+	d.started <- struct{}{}
+	<-d.gate
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, entry.Message)
+}
+
+func (d *gatedDriver) logged() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.messages
+}
+
+func entry(msg string, level logging.Level) logging.Entry {
+	return logging.Entry{Time: time.Now(), Message: msg, Level: level}
+}
+
+// fill logs a first entry, waits for the writer to pick it up and then fills the queue.
+func fill(t *testing.T, d *async.Driver, next *gatedDriver, queueSize int) {
+	t.Helper()
+
+	d.Log(context.Background(), entry("in-flight", logging.LevelInfo))
+	<-next.started
+	for i := 0; i < queueSize; i++ {
+		d.Log(context.Background(), entry("queued", logging.LevelInfo))
+	}
+}
+
+func TestDriver_Overflow(t *testing.T) {
+	t.Run("drop newest", dropNewest)
+	t.Run("drop oldest", dropOldest)
+	t.Run("drop below level", dropBelowLevel)
+	t.Run("block", block)
+}
+
+func dropNewest(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	d := async.NewDriver(next, async.Options{QueueSize: 2, Overflow: async.DropNewest})
+	fill(t, d, next, 2)
+	d.Log(context.Background(), entry("dropped", logging.LevelError))
+	require.EqualValues(t, 1, d.Dropped(), "new entry should be dropped")
+
+	close(next.gate)
+	require.NoError(t, d.Close(context.Background()))
+	require.Equal(t, []string{"in-flight", "queued", "queued"}, next.logged())
+}
+
+func dropOldest(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	d := async.NewDriver(next, async.Options{QueueSize: 2, Overflow: async.DropOldest})
+	fill(t, d, next, 2)
+	d.Log(context.Background(), entry("newest", logging.LevelInfo))
+	require.EqualValues(t, 1, d.Dropped(), "oldest entry should be dropped")
+
+	close(next.gate)
+	require.NoError(t, d.Close(context.Background()))
+	require.Equal(t, []string{"in-flight", "queued", "newest"}, next.logged())
+}
+
+func dropBelowLevel(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	d := async.NewDriver(next, async.Options{QueueSize: 1, Overflow: async.DropBelowLevel, MinLevel: logging.LevelWarn})
+	fill(t, d, next, 1)
+	d.Log(context.Background(), entry("debug", logging.LevelDebug))
+	require.EqualValues(t, 1, d.Dropped(), "entries below the min level should be dropped")
+
+	logged := make(chan struct{})
+	go func() {
+		d.Log(context.Background(), entry("error", logging.LevelError))
+		close(logged)
+	}()
+	select {
+	case <-logged:
+		t.Fatal("entries above the min level should block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(next.gate)
+	<-logged
+	require.NoError(t, d.Close(context.Background()))
+	require.Equal(t, []string{"in-flight", "queued", "error"}, next.logged())
+}
+
+func block(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	d := async.NewDriver(next, async.Options{QueueSize: 1})
+	fill(t, d, next, 1)
+	logged := make(chan struct{})
+	go func() {
+		d.Log(context.Background(), entry("blocked", logging.LevelDebug))
+		close(logged)
+	}()
+	select {
+	case <-logged:
+		t.Fatal("log should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(next.gate)
+	<-logged
+	require.NoError(t, d.Close(context.Background()))
+	require.Zero(t, d.Dropped(), "no entry should be dropped")
+	require.Equal(t, []string{"in-flight", "queued", "blocked"}, next.logged())
+}
+
+func TestDriver_Flush(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	d := async.NewDriver(next, async.Options{QueueSize: 10})
+	defer d.Close(context.Background())
+	fill(t, d, next, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, d.Flush(ctx), context.DeadlineExceeded, "flush should honor the context")
+
+	close(next.gate)
+	require.NoError(t, d.Flush(context.Background()))
+	require.Len(t, next.logged(), 4, "flush should wait for all queued entries")
+}
+
+func TestDriver_Close(t *testing.T) {
+	t.Parallel()
+
+	next := newGatedDriver()
+	close(next.gate)
+	d := async.NewDriver(next, async.Options{})
+	d.Log(context.Background(), entry("first", logging.LevelInfo))
+	require.NoError(t, d.Close(context.Background()))
+	d.Log(context.Background(), entry("after close", logging.LevelInfo))
+	require.EqualValues(t, 1, d.Dropped(), "entries logged after close should be dropped")
+	require.Equal(t, []string{"first"}, next.logged())
+}
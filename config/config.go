@@ -1,17 +1,107 @@
 package config
 
+import (
+	"time"
+)
+
 type Config struct {
 	Log         LogConfig         `yaml:"log"`
 	Transaction TransactionConfig `yaml:"transaction"`
 }
 
 type LogConfig struct {
-	Level               string              `yaml:"level"`
-	ProcessingType      string              `yaml:"processing"`
-	OutputFile          string              `yaml:"output_file"`
+	Level string `yaml:"level"`
+	// Levels sets the level of the named loggers by name prefix, e.g. `db: warn` applies to `db` and `db.mysql`.
+	Levels         map[string]string `yaml:"levels"`
+	ProcessingType string            `yaml:"processing"`
+	OutputFile     string            `yaml:"output_file"`
+	// AddSource adds the file, line and function of the call site to each entry.
+	AddSource bool `yaml:"add_source"`
+	// UnsampledLevel is the lowest level logged within the transactions whose trace is not sampled, e.g. `warn` to
+	// drop their debug and info entries. Empty logs them like any other entry.
+	UnsampledLevel string `yaml:"unsampled_level"`
+	// StackTraceLevel adds a stack trace to the entries at or above it. Empty disables stack traces.
+	StackTraceLevel     string              `yaml:"stack_trace_level"`
 	PermanentAttributes []map[string]string `yaml:"permanent_attributes"`
+	OTLP                OTLPConfig          `yaml:"otlp"`
+	// Rotation enables the rotation of the output file. Without it, the file grows indefinitely.
+	Rotation *RotationConfig `yaml:"rotation"`
+	// Outputs replaces the single output described above with several ones, each having its own settings.
+	Outputs []OutputConfig `yaml:"outputs" validate:"dive"`
+	// Redaction masks sensitive data before the entries reach the outputs.
+	Redaction *RedactionConfig `yaml:"redaction"`
+	// Sampling limits the entries repeating the same level and message.
+	Sampling *SamplingConfig `yaml:"sampling"`
+}
+
+// RedactionConfig lists the sensitive data to mask. Struct fields tagged with `log:"redact"` are always masked.
+type RedactionConfig struct {
+	// DefaultKeys adds the usual secret keys, like password, token or authorization, to Keys.
+	DefaultKeys bool `yaml:"default_keys"`
+	// Keys lists the keys of the attributes whose value is masked, compared case-insensitively.
+	Keys []string `yaml:"keys"`
+	// Patterns lists regular expressions, or the built-in email, card_number and jwt, to mask in string values.
+	Patterns []string `yaml:"patterns"`
+	// Mask replaces the redacted values. Defaults to [REDACTED].
+	Mask string `yaml:"mask"`
+}
+
+// OutputConfig describes one of several log outputs. An empty level falls back to the logger level.
+type OutputConfig struct {
+	Level             string          `yaml:"level"`
+	ProcessingType    string          `yaml:"processing"`
+	OutputFile        string          `yaml:"output_file"`
+	Rotation          *RotationConfig `yaml:"rotation"`
+	OTLP              OTLPConfig      `yaml:"otlp"`
+	IncludeAttributes []string        `yaml:"include_attributes"`
+	ExcludeAttributes []string        `yaml:"exclude_attributes"`
+}
+
+// SamplingConfig keeps the first entries of each level and message per interval, then every Mth, and limits their
+// rate. The number of suppressed entries is logged every interval.
+type SamplingConfig struct {
+	// Interval defaults to 1s.
+	Interval   time.Duration `yaml:"interval" validate:"gte=0"`
+	First      int           `yaml:"first" validate:"gte=0"`
+	Thereafter int           `yaml:"thereafter" validate:"gte=0"`
+	// Rate is the number of entries per second kept for each level and message. 0 disables the limit.
+	Rate  float64 `yaml:"rate" validate:"gte=0"`
+	Burst int     `yaml:"burst" validate:"gte=0"`
+	// KeepSampled exempts the entries of sampled transactions from the limits. Every trace is sampled unless the
+	// transaction sample_rate is below 1.
+	KeepSampled bool `yaml:"keep_sampled"`
+}
+
+type RotationConfig struct {
+	MaxSizeMB  int           `yaml:"max_size_mb" validate:"gte=0"`
+	MaxAge     time.Duration `yaml:"max_age" validate:"gte=0"`
+	MaxBackups int           `yaml:"max_backups" validate:"gte=0"`
+	Compress   bool          `yaml:"compress"`
+	LocalTime  bool          `yaml:"local_time"`
+	// ReopenOnSIGHUP reopens the file on SIGHUP, for external rotation tools.
+	ReopenOnSIGHUP bool `yaml:"reopen_on_sighup"`
+}
+
+// OTLPConfig configures the OTLP log exporter used by the `otlp` processing type.
+type OTLPConfig struct {
+	// Endpoint is the collector URL. The scheme decides whether the connection is secure.
+	Endpoint string `yaml:"endpoint" validate:"omitempty,url"`
+	// Protocol is either `grpc` or `http/protobuf`, the default.
+	Protocol      string            `yaml:"protocol" validate:"omitempty,oneof=grpc http/protobuf"`
+	Headers       map[string]string `yaml:"headers"`
+	FlushInterval time.Duration     `yaml:"flush_interval" validate:"gte=0"`
+	QueueSize     int               `yaml:"queue_size" validate:"gte=0"`
+	BatchSize     int               `yaml:"batch_size" validate:"gte=0"`
 }
 
 type TransactionConfig struct {
-	RecorderType string `yaml:"recorder"`
+	RecorderType   string `yaml:"recorder"`
+	ServiceName    string `yaml:"service_name"`
+	ServiceVersion string `yaml:"service_version"`
+	Environment    string `yaml:"environment"`
+	ServerURL      string `yaml:"server_url" validate:"omitempty,url"`
+	// SampleRate is the fraction of the new traces sampled, between 0 and 1. Defaults to 1.
+	SampleRate *float64 `yaml:"sample_rate" validate:"omitempty,gte=0,lte=1"`
+	// SampleErrors marks the transactions recording an error as sampled, so their next entries are logged.
+	SampleErrors bool `yaml:"sample_errors"`
 }
@@ -1,8 +1,9 @@
-// Package dummy is a `Recorder` interface implementation that simply generates a uuid TraceID.
+// Package dummy is a `Recorder` interface implementation that simply generates a uuid based TraceID.
 package dummy
 
 import (
 	"context"
+	"encoding/hex"
 
 	"github.com/google/uuid"
 
@@ -10,15 +11,79 @@ import (
 )
 
 // Recorder is a dummy implementation of a trace recorder.
-// It simply sets an uuid as TraceID.
-type Recorder struct{}
+// It simply sets a hex encoded uuid as TraceID and random IDs for transactions and spans.
+// Remote traces found in the context are continued, new ones are sampled by the configured sampler.
+type Recorder struct {
+	sampler transaction.Sampler
+	// sampleErrors marks the transactions recording an error as sampled.
+	sampleErrors bool
+}
+
+type Option func(*Recorder)
+
+// WithSampler sets the sampler deciding whether new traces are sampled. Defaults to transaction.AlwaysSample().
+func WithSampler(sampler transaction.Sampler) Option {
+	return func(r *Recorder) {
+		r.sampler = sampler
+	}
+}
 
-func NewRecorder() *Recorder {
-	return &Recorder{}
+// WithErrorSampling marks the transactions recording an error as sampled, so their next entries are logged and
+// the flag is propagated to the downstream services.
+func WithErrorSampling() Option {
+	return func(r *Recorder) {
+		r.sampleErrors = true
+	}
+}
+
+func NewRecorder(opts ...Option) *Recorder {
+	r := &Recorder{
+		sampler: transaction.AlwaysSample(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *Recorder) RecordTransaction(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context) {
-	return &transaction.Transaction{
-		TraceID: uuid.New().String(),
+	if parent, ok := transaction.TraceParentFromContext(ctx); ok {
+		tx := &transaction.Transaction{
+			TraceID:    parent.TraceID,
+			ID:         newID(),
+			ParentID:   parent.ParentID,
+			TraceState: parent.State,
+		}
+		tx.SetSampled(parent.Sampled)
+		return tx, ctx
+	}
+	uid := uuid.New()
+	tx := &transaction.Transaction{
+		TraceID: hex.EncodeToString(uid[:]),
+		ID:      newID(),
+	}
+	tx.SetSampled(r.sampler.ShouldSample(tx.TraceID))
+	return tx, ctx
+}
+
+func (r *Recorder) EndTransaction(ctx context.Context, tx *transaction.Transaction) {}
+
+func (r *Recorder) RecordSpan(ctx context.Context, tx *transaction.Transaction, name, spanType string) (*transaction.Span, context.Context) {
+	return &transaction.Span{
+		ID: newID(),
 	}, ctx
 }
+
+func (r *Recorder) EndSpan(ctx context.Context, span *transaction.Span) {}
+
+func (r *Recorder) RecordError(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error) {
+	if r.sampleErrors {
+		tx.SetSampled(true)
+	}
+}
+
+// newID generates a random 8-byte hex encoded identifier.
+func newID() string {
+	id := uuid.New()
+	return hex.EncodeToString(id[:8])
+}
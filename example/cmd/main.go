@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/urfave/cli/v2"
 
@@ -69,5 +71,10 @@ func startServer(cliCtx *cli.Context) error {
 	}()
 
 	log.Info(cliCtx.Context, "server stopped", "reason", <-exitChan)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := log.Shutdown(ctx); err != nil {
+		return fmt.Errorf("shutdown logging: %w", err)
+	}
 	return nil
 }
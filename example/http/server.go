@@ -8,6 +8,7 @@ import (
 
 	"github.com/silvan-talos/tlp/example/user"
 	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/middleware"
 )
 
 type Server struct {
@@ -20,7 +21,7 @@ func NewServer(us *user.Service) *Server {
 	}
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
-	r.Use(gin.Recovery())
+	r.Use(middleware.Gin(middleware.WithAccessLog(log.Default())), middleware.GinRecovery())
 
 	userRoutes := r.Group("/users")
 	{
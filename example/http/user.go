@@ -8,8 +8,6 @@ import (
 
 	"github.com/silvan-talos/tlp/example/user"
 	"github.com/silvan-talos/tlp/log"
-	"github.com/silvan-talos/tlp/logging"
-	"github.com/silvan-talos/tlp/transaction"
 )
 
 type userHandler struct {
@@ -24,15 +22,7 @@ func (uh *userHandler) addRoutes(r *gin.RouterGroup) {
 }
 
 func (uh *userHandler) getUser(c *gin.Context) {
-	tx, ctx := transaction.DefaultTracer().StartTransaction(c.Request.Context(),
-		"get user",
-		"request",
-		logging.NewAttr("requestPath", c.Request.URL.Path),
-		logging.NewAttr("requestMethod", c.Request.Method),
-		logging.NewAttr("callerIP", c.Request.RemoteAddr),
-		logging.NewAttr("userAgent", c.Request.UserAgent()),
-	)
-	defer tx.End()
+	ctx := c.Request.Context()
 
 	stringID := c.Param("id")
 	id, err := strconv.ParseInt(stringID, 10, 64)
@@ -50,15 +40,7 @@ func (uh *userHandler) getUser(c *gin.Context) {
 }
 
 func (uh *userHandler) createUser(c *gin.Context) {
-	tx, ctx := transaction.DefaultTracer().StartTransaction(c.Request.Context(),
-		"create user",
-		"request",
-		logging.NewAttr("requestPath", c.Request.URL.Path),
-		logging.NewAttr("requestMethod", c.Request.Method),
-		logging.NewAttr("callerIP", c.Request.RemoteAddr),
-		logging.NewAttr("userAgent", c.Request.UserAgent()),
-	)
-	defer tx.End()
+	ctx := c.Request.Context()
 
 	var req UserCreation
 	if err := c.ShouldBind(&req); err != nil {
@@ -84,15 +66,7 @@ type UserCreation struct {
 }
 
 func (uh *userHandler) updateUser(c *gin.Context) {
-	tx, ctx := transaction.DefaultTracer().StartTransaction(c.Request.Context(),
-		"update user",
-		"request",
-		logging.NewAttr("requestPath", c.Request.URL.Path),
-		logging.NewAttr("requestMethod", c.Request.Method),
-		logging.NewAttr("callerIP", c.Request.RemoteAddr),
-		logging.NewAttr("userAgent", c.Request.UserAgent()),
-	)
-	defer tx.End()
+	ctx := c.Request.Context()
 
 	stringID := c.Param("id")
 	id, err := strconv.ParseInt(stringID, 10, 64)
@@ -124,15 +98,7 @@ type UserUpdate struct {
 }
 
 func (uh *userHandler) deleteUser(c *gin.Context) {
-	tx, ctx := transaction.DefaultTracer().StartTransaction(c.Request.Context(),
-		"delete user",
-		"request",
-		logging.NewAttr("requestPath", c.Request.URL.Path),
-		logging.NewAttr("requestMethod", c.Request.Method),
-		logging.NewAttr("callerIP", c.Request.RemoteAddr),
-		logging.NewAttr("userAgent", c.Request.UserAgent()),
-	)
-	defer tx.End()
+	ctx := c.Request.Context()
 
 	stringID := c.Param("id")
 	id, err := strconv.ParseInt(stringID, 10, 64)
@@ -11,7 +11,7 @@ import (
 
 type User struct {
 	ID   int64
-	Name string
+	Name string `log:"redact"`
 	Age  int
 }
 
@@ -0,0 +1,100 @@
+// Package fanout provides a driver that sends every entry to several outputs, each with its own level and attribute filters.
+package fanout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// driver is the log.Driver interface, declared here to avoid depending on the log package.
+type driver interface {
+	Log(ctx context.Context, entry logging.Entry)
+}
+
+type Output struct {
+	Driver driver
+	// Level is the lowest level sent to the output.
+	Level logging.Level
+	// Include, if not empty, keeps only the attributes with the listed keys.
+	Include []string
+	// Exclude drops the attributes with the listed keys.
+	Exclude []string
+}
+
+// Driver logs each entry to all the outputs accepting its level.
+// A panicking output is reported on stderr and does not prevent the others from logging.
+type Driver struct {
+	outputs []Output
+}
+
+func NewDriver(outputs ...Output) *Driver {
+	return &Driver{
+		outputs: outputs,
+	}
+}
+
+func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
+	for i := range d.outputs {
+		out := &d.outputs[i]
+		if entry.Level < out.Level {
+			continue
+		}
+		filtered := entry
+		filtered.Attrs = out.filter(entry.Attrs)
+		filtered.TransactionAttrs = out.filter(entry.TransactionAttrs)
+		logTo(ctx, out.Driver, filtered)
+	}
+}
+
+// Flush flushes the outputs supporting it.
+func (d *Driver) Flush(ctx context.Context) error {
+	var errs []error
+	for _, out := range d.outputs {
+		if f, ok := out.Driver.(interface{ Flush(context.Context) error }); ok {
+			errs = append(errs, f.Flush(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the outputs supporting it.
+func (d *Driver) Close(ctx context.Context) error {
+	var errs []error
+	for _, out := range d.outputs {
+		if c, ok := out.Driver.(interface{ Close(context.Context) error }); ok {
+			errs = append(errs, c.Close(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func logTo(ctx context.Context, d driver, entry logging.Entry) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintln(os.Stderr, "fanout: output failed:", r)
+		}
+	}()
+	d.Log(ctx, entry)
+}
+
+func (o *Output) filter(attrs []logging.Attr) []logging.Attr {
+	if len(o.Include) == 0 && len(o.Exclude) == 0 {
+		return attrs
+	}
+	filtered := make([]logging.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		if len(o.Include) > 0 && !slices.Contains(o.Include, attr.Key) {
+			continue
+		}
+		if slices.Contains(o.Exclude, attr.Key) {
+			continue
+		}
+		filtered = append(filtered, attr)
+	}
+	return filtered
+}
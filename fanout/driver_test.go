@@ -0,0 +1,80 @@
+package fanout_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/fanout"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+)
+
+func TestDriver_Log(t *testing.T) {
+	t.Run("per output level", perOutputLevel)
+	t.Run("attribute filters", attributeFilters)
+	t.Run("failing output", failingOutput)
+}
+
+func entry(level logging.Level, attrs ...logging.Attr) logging.Entry {
+	return logging.Entry{Time: time.Now(), Message: "test", Level: level, Attrs: attrs}
+}
+
+func perOutputLevel(t *testing.T) {
+	t.Parallel()
+
+	debug, warn := &mock.Driver{}, &mock.Driver{}
+	d := fanout.NewDriver(
+		fanout.Output{Driver: debug, Level: logging.LevelDebug},
+		fanout.Output{Driver: warn, Level: logging.LevelWarn},
+	)
+	d.Log(context.Background(), entry(logging.LevelDebug))
+	d.Log(context.Background(), entry(logging.LevelInfo))
+	d.Log(context.Background(), entry(logging.LevelError))
+	require.Equal(t, 3, debug.Count, "debug output should receive all entries")
+	require.Equal(t, 1, warn.Count, "warn output should only receive entries at or above warn")
+}
+
+func attributeFilters(t *testing.T) {
+	t.Parallel()
+
+	attrs := []logging.Attr{
+		logging.NewAttr("id", 12),
+		logging.NewAttr("email", "john@example.com"),
+		logging.NewAttr("query", "select"),
+	}
+	var all, included, excluded []logging.Attr
+	d := fanout.NewDriver(
+		fanout.Output{Driver: &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) { all = entry.Attrs }}},
+		fanout.Output{
+			Driver:  &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) { included = entry.Attrs }},
+			Include: []string{"id", "query"},
+			Exclude: []string{"query"},
+		},
+		fanout.Output{
+			Driver:  &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) { excluded = entry.Attrs }},
+			Exclude: []string{"email"},
+		},
+	)
+	d.Log(context.Background(), entry(logging.LevelInfo, attrs...))
+	require.Equal(t, attrs, all, "outputs without filters should receive all attrs")
+	require.Equal(t, []logging.Attr{logging.NewAttr("id", 12)}, included)
+	require.Equal(t, []logging.Attr{logging.NewAttr("id", 12), logging.NewAttr("query", "select")}, excluded)
+	require.Len(t, attrs, 3, "original attrs should not be affected")
+}
+
+func failingOutput(t *testing.T) {
+	t.Parallel()
+
+	healthy := &mock.Driver{}
+	d := fanout.NewDriver(
+		fanout.Output{Driver: &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) { panic("disk full") }}},
+		fanout.Output{Driver: healthy},
+	)
+	require.NotPanics(t, func() {
+		d.Log(context.Background(), entry(logging.LevelInfo))
+	})
+	require.Equal(t, 1, healthy.Count, "a failing output should not affect the others")
+}
@@ -2,17 +2,26 @@
 package json
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	stdjson "encoding/json"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/silvan-talos/tlp/logging"
 )
 
+var bufPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// Driver writes every entry as a JSON line. It is safe for concurrent use: each entry is encoded
+// separately and written to the output with a single call.
 type Driver struct {
-	writer *bufio.Writer
+	mu     sync.Mutex
+	output io.Writer
 }
 
 func NewDriver(output io.Writer) *Driver {
@@ -20,11 +29,20 @@ func NewDriver(output io.Writer) *Driver {
 		output = os.Stdout
 	}
 	return &Driver{
-		writer: bufio.NewWriter(output),
+		output: output,
 	}
 }
 
 func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
-	_ = stdjson.NewEncoder(d.writer).Encode(entry)
-	_ = d.writer.Flush()
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+	entry.Attrs = logging.ResolveAttrs(entry.Attrs)
+	entry.TransactionAttrs = logging.ResolveAttrs(entry.TransactionAttrs)
+	buf.Write(appendEntry(buf.AvailableBuffer(), entry))
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = d.output.Write(buf.Bytes())
 }
@@ -0,0 +1,93 @@
+package json_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/json"
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestDriver_ConcurrentLog(t *testing.T) {
+	t.Parallel()
+
+	const (
+		goroutines = 50
+		entries    = 100
+	)
+	var out bytes.Buffer
+	logger := log.NewLogger(json.NewDriver(&out), logging.LevelDebug).WithAttrs(logging.NewAttr("env", "test"))
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, ctx := tracer.StartTransaction(context.Background(), "test", "race-test")
+			defer tx.End()
+			for i := 0; i < entries; i++ {
+				logger.Info(ctx, fmt.Sprintf("goroutine %d entry %d", g, i), "goroutine", g, "entry", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var entry struct {
+			Message string
+			TraceID string
+			Attrs   []logging.Attr
+		}
+		require.NoError(t, stdjson.Unmarshal(scanner.Bytes(), &entry), "every line should be an intact JSON object")
+		require.Equal(t, "test-trace", entry.TraceID)
+		require.Len(t, entry.Attrs, 3, "entry attrs should not leak between goroutines")
+		require.Equal(t, fmt.Sprintf("goroutine %v entry %v", entry.Attrs[1].Value, entry.Attrs[2].Value), entry.Message)
+		seen[entry.Message] = true
+	}
+	require.Len(t, seen, goroutines*entries, "every entry should be written exactly once")
+}
+
+func TestDriver_LogError(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	json.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Message: "update user",
+		Attrs:   []logging.Attr{logging.NewAttr("err", fmt.Errorf("update user: %w", errors.New("bind failed")))},
+	})
+	require.Contains(t, out.String(), `{"Key":"err","Value":{"message":"update user: bind failed","type":"*fmt.wrapError",`+
+		`"causes":[{"message":"bind failed","type":"*errors.errorString"}]}}`)
+}
+
+type userValuer struct {
+	id int
+}
+
+func (u userValuer) LogValue() logging.Value {
+	return logging.GroupValue(logging.Int("id", u.id))
+}
+
+func TestDriver_LogValuer(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	json.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Message:          "user updated",
+		TransactionAttrs: []logging.Attr{logging.Any("user", userValuer{id: 12})},
+	})
+	require.Contains(t, out.String(), `"TransactionAttrs":[{"Key":"user","Value":{"id":12}}]`)
+}
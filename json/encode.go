@@ -0,0 +1,202 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// appendEntry encodes the entry the way encoding/json would, without reflection, so logging scalar attribute values
+// does not allocate.
+func appendEntry(dst []byte, entry logging.Entry) []byte {
+	dst = append(dst, `{"Time":`...)
+	dst = appendTime(dst, entry.Time)
+	dst = append(dst, `,"Message":`...)
+	dst = appendString(dst, entry.Message)
+	dst = append(dst, `,"Level":`...)
+	dst = strconv.AppendInt(dst, int64(entry.Level), 10)
+	if entry.Logger != "" {
+		dst = append(dst, `,"Logger":`...)
+		dst = appendString(dst, entry.Logger)
+	}
+	if entry.Source != nil {
+		dst = append(dst, `,"Source":`...)
+		dst = appendSource(dst, *entry.Source)
+	}
+	if len(entry.Stack) > 0 {
+		dst = append(dst, `,"Stack":[`...)
+		for i, frame := range entry.Stack {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendSource(dst, frame)
+		}
+		dst = append(dst, ']')
+	}
+	dst = append(dst, `,"Attrs":`...)
+	dst = appendAttrs(dst, entry.Attrs)
+	dst = append(dst, `,"TraceID":`...)
+	dst = appendString(dst, entry.TraceID)
+	dst = append(dst, `,"SpanID":`...)
+	dst = appendString(dst, entry.SpanID)
+	dst = append(dst, `,"TransactionAttrs":`...)
+	dst = appendAttrs(dst, entry.TransactionAttrs)
+	return append(dst, '}', '\n')
+}
+
+func appendSource(dst []byte, source logging.Source) []byte {
+	dst = append(dst, `{"Function":`...)
+	dst = appendString(dst, source.Function)
+	dst = append(dst, `,"File":`...)
+	dst = appendString(dst, source.File)
+	dst = append(dst, `,"Line":`...)
+	dst = strconv.AppendInt(dst, int64(source.Line), 10)
+	return append(dst, '}')
+}
+
+func appendAttrs(dst []byte, attrs []logging.Attr) []byte {
+	if attrs == nil {
+		return append(dst, "null"...)
+	}
+	dst = append(dst, '[')
+	for i, attr := range attrs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, `{"Key":`...)
+		dst = appendString(dst, attr.Key)
+		dst = append(dst, `,"Value":`...)
+		dst = appendValue(dst, attr.Value)
+		dst = append(dst, '}')
+	}
+	return append(dst, ']')
+}
+
+func appendValue(dst []byte, v logging.Value) []byte {
+	switch v.Kind() {
+	case logging.KindString:
+		return appendString(dst, v.String())
+	case logging.KindInt64:
+		return strconv.AppendInt(dst, v.Int64(), 10)
+	case logging.KindUint64:
+		return strconv.AppendUint(dst, v.Uint64(), 10)
+	case logging.KindFloat64:
+		return appendFloat(dst, v.Float64())
+	case logging.KindBool:
+		return strconv.AppendBool(dst, v.Bool())
+	case logging.KindDuration:
+		return strconv.AppendInt(dst, int64(v.Duration()), 10)
+	case logging.KindTime:
+		return appendTime(dst, v.Time())
+	case logging.KindGroup:
+		dst = append(dst, '{')
+		for i, attr := range v.Group() {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendString(dst, attr.Key)
+			dst = append(dst, ':')
+			dst = appendValue(dst, attr.Value)
+		}
+		return append(dst, '}')
+	default:
+		value := v.Any()
+		// most error types have no exported fields, so they are encoded through their details
+		if err, ok := value.(error); ok {
+			value = logging.NewErrorDetails(err)
+		}
+		b, err := stdjson.Marshal(value)
+		if err != nil {
+			// keep the entry rather than failing on a single value
+			return appendString(dst, fmt.Sprintf("%+v", value))
+		}
+		return append(dst, b...)
+	}
+}
+
+func appendTime(dst []byte, t time.Time) []byte {
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"')
+}
+
+// appendFloat formats f like encoding/json. NaN and infinities, not supported by JSON, are written as strings.
+func appendFloat(dst []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendString(dst, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+	return dst
+}
+
+const hex = "0123456789abcdef"
+
+// appendString quotes s like encoding/json, including the escaping of HTML characters.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '\\', '"':
+				dst = append(dst, '\\', b)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are escaped for JSONP, like encoding/json does
+		if c == '\u2028' || c == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
@@ -0,0 +1,52 @@
+package json
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+func TestAppendEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]logging.Entry{
+		"empty": {},
+		"full": {
+			Time:    time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC),
+			Message: "update <user> \"12\"\n\tfailed   \xff",
+			Level:   logging.LevelWarn,
+			Logger:  "db.mysql",
+			Source:  &logging.Source{Function: "main.update", File: "/app/user.go", Line: 12},
+			Stack:   []logging.Source{{Function: "main.main", File: "/app/main.go", Line: 5}},
+			Attrs: []logging.Attr{
+				logging.String("path", "/users/12?a=1&b=2"),
+				logging.Int("status", -200),
+				logging.Uint64("size", math.MaxUint64),
+				logging.Float64("ratio", 0.25),
+				logging.Float64("tiny", 1e-9),
+				logging.Float64("huge", 1e22),
+				logging.Bool("cached", true),
+				logging.Duration("elapsed", 1500*time.Microsecond),
+				logging.Time("at", time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("", 3600))),
+				logging.Group("user", logging.Int("id", 12), logging.String("name", "ann")),
+				logging.NewAttr("tags", []string{"a", "b"}),
+			},
+			TraceID:          "test-trace",
+			SpanID:           "test-span",
+			TransactionAttrs: []logging.Attr{},
+		},
+	}
+	for name, entry := range tests {
+		t.Run(name, func(t *testing.T) {
+			var expected bytes.Buffer
+			require.NoError(t, stdjson.NewEncoder(&expected).Encode(entry))
+			require.Equal(t, expected.String(), string(appendEntry(nil, entry)), "output should match encoding/json")
+		})
+	}
+}
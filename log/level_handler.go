@@ -0,0 +1,208 @@
+package log
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+var levelRegistry = struct {
+	sync.RWMutex
+	levels map[string]*logging.LevelVar
+}{levels: make(map[string]*logging.LevelVar)}
+
+// RegisterLevel makes the level of a named logger controllable through the LevelHandler.
+// Registering a name again replaces the previous level variable.
+func RegisterLevel(name string, level *logging.LevelVar) {
+	levelRegistry.Lock()
+	defer levelRegistry.Unlock()
+	levelRegistry.levels[name] = level
+}
+
+// configureLevel sets the level registered for name, registering it if needed, so the loggers already using
+// it pick up the change.
+func configureLevel(name string, level logging.Level) {
+	levelRegistry.Lock()
+	defer levelRegistry.Unlock()
+	if v, ok := levelRegistry.levels[name]; ok {
+		v.Set(level)
+		return
+	}
+	levelRegistry.levels[name] = logging.NewLevelVar(level)
+}
+
+// unregisterLevel removes the level configured for name, setting it first to level for the loggers already using it.
+func unregisterLevel(name string, level logging.Level) {
+	levelRegistry.Lock()
+	defer levelRegistry.Unlock()
+	if v, ok := levelRegistry.levels[name]; ok {
+		v.Set(level)
+		delete(levelRegistry.levels, name)
+	}
+}
+
+// matchingLevel returns the level registered for the longest dot-separated prefix of name.
+func matchingLevel(name string) (*logging.LevelVar, bool) {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	for {
+		if level, ok := levelRegistry.levels[name]; ok {
+			return level, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return nil, false
+		}
+		name = name[:i]
+	}
+}
+
+func registeredLevel(name string) (*logging.LevelVar, bool) {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	level, ok := levelRegistry.levels[name]
+	return level, ok
+}
+
+func registeredLevels() map[string]string {
+	levelRegistry.RLock()
+	defer levelRegistry.RUnlock()
+	levels := make(map[string]string, len(levelRegistry.levels))
+	for name, level := range levelRegistry.levels {
+		levels[name] = level.Level().String()
+	}
+	return levels
+}
+
+type levelState struct {
+	Logger  string            `json:"logger,omitempty"`
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers,omitempty"`
+	// RevertAt is the time when a temporary level is reverted.
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+type levelUpdate struct {
+	Level string `json:"level"`
+	// TTL is a duration like "15m" after which the previous level is restored. Empty means permanent.
+	TTL string `json:"ttl"`
+}
+
+// pendingRevert restores a level raised temporarily.
+type pendingRevert struct {
+	timer    *time.Timer
+	previous logging.Level
+	at       time.Time
+}
+
+// LevelHandler exposes the level of the default logger, or of a registered logger selected with the `logger`
+// query parameter, over HTTP:
+//
+//	GET returns the current level, along with the levels of the registered loggers for the default logger.
+//	PUT sets the level from a JSON body like {"level": "debug", "ttl": "15m"}. With a TTL, the previous level is
+//	restored once it expires.
+//
+// Like SetLevel, a PUT affects the clones sharing the level variable, not those made with WithLevel. Outputs
+// configured with their own level keep filtering at it, so lowering the default level does not make them log more.
+type LevelHandler struct {
+	mu      sync.Mutex
+	reverts map[*logging.LevelVar]*pendingRevert
+}
+
+func NewLevelHandler() *LevelHandler {
+	return &LevelHandler{
+		reverts: make(map[*logging.LevelVar]*pendingRevert),
+	}
+}
+
+func (h *LevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("logger")
+	level := Default().level
+	if name != "" {
+		var ok bool
+		if level, ok = registeredLevel(name); !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": fmt.Sprintf("logger %q not found", name)})
+			return
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var update levelUpdate
+		if err := stdjson.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+			return
+		}
+		if err := h.update(level, update); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	state := levelState{
+		Logger: name,
+		Level:  level.Level().String(),
+	}
+	if name == "" {
+		state.Loggers = registeredLevels()
+	}
+	h.mu.Lock()
+	if revert, ok := h.reverts[level]; ok {
+		state.RevertAt = &revert.at
+	}
+	h.mu.Unlock()
+	writeJSON(w, http.StatusOK, state)
+}
+
+func (h *LevelHandler) update(level *logging.LevelVar, update levelUpdate) error {
+	lvl, err := logging.ParseLevel(update.Level)
+	if err != nil {
+		return err
+	}
+	var ttl time.Duration
+	if update.TTL != "" {
+		if ttl, err = time.ParseDuration(update.TTL); err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid ttl: %s", update.TTL)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	previous := level.Level()
+	if revert, ok := h.reverts[level]; ok {
+		// keep reverting to the level set before the first temporary change
+		revert.timer.Stop()
+		previous = revert.previous
+		delete(h.reverts, level)
+	}
+	level.Set(lvl)
+	if ttl == 0 {
+		return nil
+	}
+	revert := &pendingRevert{previous: previous, at: time.Now().Add(ttl)}
+	revert.timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.reverts[level] != revert {
+			return
+		}
+		delete(h.reverts, level)
+		level.Set(revert.previous)
+	})
+	h.reverts[level] = revert
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = stdjson.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,108 @@
+package log_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+)
+
+type levelResponse struct {
+	Logger   string            `json:"logger"`
+	Level    string            `json:"level"`
+	Loggers  map[string]string `json:"loggers"`
+	RevertAt *time.Time        `json:"revertAt"`
+}
+
+func serveLevel(t *testing.T, h http.Handler, method, target, body string) (int, levelResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	var resp levelResponse
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec.Code, resp
+}
+
+// TestLevelHandler replaces the default logger, so it must not run in parallel with other tests.
+func TestLevelHandler(t *testing.T) {
+	original := log.Default()
+	t.Cleanup(original.SetDefault)
+
+	var count int
+	logger := log.NewLogger(&mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) { count++ }}, logging.LevelInfo)
+	logger.SetDefault()
+	named := logging.NewLevelVar(logging.LevelWarn)
+	log.RegisterLevel("handler-test", named)
+	h := log.NewLevelHandler()
+
+	t.Run("get default level", func(t *testing.T) {
+		code, resp := serveLevel(t, h, http.MethodGet, "/", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "INFO", resp.Level)
+		require.Equal(t, "WARN", resp.Loggers["handler-test"], "registered loggers should be listed")
+	})
+
+	t.Run("set level permanently", func(t *testing.T) {
+		code, resp := serveLevel(t, h, http.MethodPut, "/", `{"level":"debug"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "DEBUG", resp.Level)
+		require.Nil(t, resp.RevertAt)
+		log.Debug(context.Background(), "debug enabled")
+		require.Equal(t, 1, count, "debug logs should be written")
+		serveLevel(t, h, http.MethodPut, "/", `{"level":"info"}`)
+	})
+
+	t.Run("clones", func(t *testing.T) {
+		withAttrs := log.Default().WithAttrs(logging.String("env", "test"))
+		withLevel, err := log.Default().WithLevel("error")
+		require.NoError(t, err)
+		serveLevel(t, h, http.MethodPut, "/", `{"level":"debug"}`)
+		require.Equal(t, logging.LevelDebug, withAttrs.Level(), "WithAttrs clones should share the level")
+		require.Equal(t, logging.LevelError, withLevel.Level(), "WithLevel clones should keep their own level")
+		serveLevel(t, h, http.MethodPut, "/", `{"level":"info"}`)
+	})
+
+	t.Run("revert temporary level", func(t *testing.T) {
+		code, resp := serveLevel(t, h, http.MethodPut, "/?logger=handler-test", `{"level":"debug","ttl":"50ms"}`)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "handler-test", resp.Logger)
+		require.Equal(t, "DEBUG", resp.Level)
+		require.NotNil(t, resp.RevertAt)
+		serveLevel(t, h, http.MethodPut, "/?logger=handler-test", `{"level":"error","ttl":"50ms"}`)
+		require.Equal(t, logging.LevelError, named.Level())
+		require.Eventually(t, func() bool {
+			return named.Level() == logging.LevelWarn
+		}, time.Second, 10*time.Millisecond, "level before the first change should be restored")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name, method, target, body string
+			code                       int
+		}{
+			{name: "invalid level", method: http.MethodPut, target: "/", body: `{"level":"verbose"}`, code: http.StatusBadRequest},
+			{name: "invalid ttl", method: http.MethodPut, target: "/", body: `{"level":"debug","ttl":"soon"}`, code: http.StatusBadRequest},
+			{name: "invalid body", method: http.MethodPut, target: "/", body: `level=debug`, code: http.StatusBadRequest},
+			{name: "unknown logger", method: http.MethodGet, target: "/?logger=missing", code: http.StatusNotFound},
+			{name: "unsupported method", method: http.MethodPost, target: "/", code: http.StatusMethodNotAllowed},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				code, _ := serveLevel(t, h, tt.method, tt.target, tt.body)
+				require.Equal(t, tt.code, code)
+			})
+		}
+		require.Equal(t, logging.LevelInfo, logger.Level(), "level should be unchanged")
+	})
+}
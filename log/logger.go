@@ -4,17 +4,29 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/silvan-talos/tlp/apm"
 	"github.com/silvan-talos/tlp/config"
 	"github.com/silvan-talos/tlp/dummy"
+	"github.com/silvan-talos/tlp/fanout"
 	"github.com/silvan-talos/tlp/json"
 	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/otel"
+	"github.com/silvan-talos/tlp/otlp"
+	"github.com/silvan-talos/tlp/redact"
+	"github.com/silvan-talos/tlp/rotate"
+	"github.com/silvan-talos/tlp/sample"
 	"github.com/silvan-talos/tlp/text"
 	"github.com/silvan-talos/tlp/transaction"
 )
@@ -34,62 +46,179 @@ func init() {
 }
 
 func interpretConfig(cfg config.Config) {
-	var recorder transaction.Recorder
-	if cfg.Transaction.RecorderType != "" && strings.EqualFold(cfg.Transaction.RecorderType, "apm") {
-		recorder = apm.NewRecorder()
-	} else {
-		recorder = dummy.NewRecorder()
+	var recorder transaction.Recorder = newDummyRecorder(cfg.Transaction)
+	switch strings.ToLower(cfg.Transaction.RecorderType) {
+	case "apm":
+		apmRecorder, err := apm.NewRecorder(cfg.Transaction)
+		if err == nil {
+			recorder = apmRecorder
+			addShutdownHook(func(ctx context.Context) error {
+				apmRecorder.Close()
+				return nil
+			})
+		} else {
+			fmt.Println("create apm recorder", err)
+		}
+	case "otel":
+		provider, err := otel.NewTracerProvider(cfg.Transaction)
+		if err == nil {
+			var opts []otel.Option
+			if cfg.Transaction.SampleErrors {
+				opts = append(opts, otel.WithErrorSampling())
+			}
+			recorder = otel.NewRecorder(provider, opts...)
+			addShutdownHook(provider.Shutdown)
+		} else {
+			fmt.Println("create otel tracer provider", err)
+		}
 	}
 	transaction.SetDefaultTracer(transaction.NewTracer(recorder))
 	defaultLogger.Store(NewLoggerFromConfig(cfg.Log))
 }
 
+var shutdown struct {
+	mu    sync.Mutex
+	hooks []func(ctx context.Context) error
+}
+
+// addShutdownHook registers a function releasing a resource created from the config file.
+func addShutdownHook(hook func(ctx context.Context) error) {
+	shutdown.mu.Lock()
+	defer shutdown.mu.Unlock()
+	shutdown.hooks = append(shutdown.hooks, hook)
+}
+
+// Shutdown closes the default logger and flushes and stops the transaction recorder created from the config file, so
+// the entries and spans still buffered are sent before the process exits. It should be called once, when the
+// application stops.
+func Shutdown(ctx context.Context) error {
+	shutdown.mu.Lock()
+	hooks := shutdown.hooks
+	shutdown.hooks = nil
+	shutdown.mu.Unlock()
+	var errs []error
+	if logger := Default(); logger != nil {
+		if err := logger.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newDummyRecorder creates the default recorder, sampling the new traces as configured.
+func newDummyRecorder(cfg config.TransactionConfig) *dummy.Recorder {
+	var opts []dummy.Option
+	if cfg.SampleRate != nil {
+		opts = append(opts, dummy.WithSampler(transaction.RatioSampler(*cfg.SampleRate)))
+	}
+	if cfg.SampleErrors {
+		opts = append(opts, dummy.WithErrorSampling())
+	}
+	return dummy.NewRecorder(opts...)
+}
+
+// Driver writes the entries of a logger to an output.
+//
+// The attribute slices of the entry, Attrs and TransactionAttrs, are only valid during the Log call: the logger reuses
+// them for the next entries once Log returns. A driver keeping an entry, e.g. to write it asynchronously, must keep
+// entry.Clone() instead. Log may be called concurrently.
 type Driver interface {
 	Log(ctx context.Context, entry logging.Entry)
 }
 
 type Logger struct {
 	driver Driver
-	level  logging.Level
-	attrs  []logging.Attr
+	name   string
+	// addSource enables capturing the call site of each entry.
+	addSource bool
+	// stackLevel is the lowest level of the entries getting a stack trace, nil if disabled.
+	stackLevel *logging.Level
+	// unsampledLevel is the lowest level of the entries logged within unsampled transactions, nil if not filtered.
+	unsampledLevel *logging.Level
+	// redactor masks the sensitive data of the entries, nil if disabled.
+	redactor redact.Redactor
+	// level is shared with the clones created by WithAttrs, so changing it affects all of them.
+	level *logging.LevelVar
+	attrs []logging.Attr
+	// groups are the groups opened with WithGroup, holding the attrs added after opening them.
+	groups []group
+}
+
+type group struct {
+	name  string
+	attrs []logging.Attr
 }
 
 func NewLogger(driver Driver, level logging.Level) *Logger {
 	return &Logger{
 		driver: driver,
-		level:  level,
+		level:  logging.NewLevelVar(level),
 	}
 }
 
 func NewLoggerFromConfig(cfg config.LogConfig) *Logger {
-	output := os.Stdout
-	if cfg.OutputFile != "" {
-		f, err := os.OpenFile(cfg.OutputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err == nil {
-			output = f
-		} else {
-			fmt.Println("open file", err)
-		}
-	}
-	var driver Driver
-	switch cfg.ProcessingType {
-	case "json":
-		driver = json.NewDriver(output)
-	default:
-		driver = text.NewDriver(output)
-	}
 	lvl := logging.LevelInfo
 	if cfg.Level != "" {
 		if l, err := logging.ParseLevel(cfg.Level); err == nil {
 			lvl = l
 		}
 	}
+	outputLevel := lvl
+	for prefix, level := range cfg.Levels {
+		if l, err := logging.ParseLevel(level); err == nil {
+			configureLevel(prefix, l)
+			// outputs without their own level must let through the entries of the named loggers
+			outputLevel = min(outputLevel, l)
+		}
+	}
+	var driver Driver
+	if len(cfg.Outputs) > 0 {
+		// the logger lets through what any output accepts, each output filtering by its own level
+		lvl = minOutputLevel(cfg.Outputs, lvl)
+		driver = newFanoutDriver(cfg.Outputs, outputLevel)
+	} else {
+		driver = newDriver(config.OutputConfig{
+			ProcessingType: cfg.ProcessingType,
+			OutputFile:     cfg.OutputFile,
+			Rotation:       cfg.Rotation,
+			OTLP:           cfg.OTLP,
+		})
+	}
+	if s := cfg.Sampling; s != nil {
+		driver = sample.NewDriver(driver, sample.Options{
+			Interval:    s.Interval,
+			First:       s.First,
+			Thereafter:  s.Thereafter,
+			Rate:        s.Rate,
+			Burst:       s.Burst,
+			KeepSampled: s.KeepSampled,
+		})
+	}
 	logger := NewLogger(driver, lvl)
+	logger.addSource = cfg.AddSource
+	if cfg.StackTraceLevel != "" {
+		if l, err := logging.ParseLevel(cfg.StackTraceLevel); err == nil {
+			logger.stackLevel = &l
+		}
+	}
+	if cfg.UnsampledLevel != "" {
+		if l, err := logging.ParseLevel(cfg.UnsampledLevel); err == nil {
+			logger.unsampledLevel = &l
+		}
+	}
+	if cfg.Redaction != nil {
+		logger.redactor = newRedactor(*cfg.Redaction)
+	}
 	if cfg.PermanentAttributes != nil {
 		attrs := make([]logging.Attr, 0, 1)
 		for _, item := range cfg.PermanentAttributes {
 			for k, v := range item {
-				attrs = append(attrs, logging.Attr{Key: k, Value: v})
+				attrs = append(attrs, logging.String(k, v))
 			}
 		}
 		logger = logger.WithAttrs(attrs...)
@@ -97,6 +226,144 @@ func NewLoggerFromConfig(cfg config.LogConfig) *Logger {
 	return logger
 }
 
+// newRedactor creates the redaction rules. Invalid patterns are reported and skipped, the valid ones still apply.
+func newRedactor(cfg config.RedactionConfig) redact.Redactor {
+	patterns, err := redact.CompilePatterns(cfg.Patterns)
+	if err != nil {
+		fmt.Println("invalid redaction patterns", err)
+	}
+	keys := cfg.Keys
+	if cfg.DefaultKeys {
+		keys = append(slices.Clip(keys), redact.DefaultKeys...)
+	}
+	return redact.New(redact.Options{
+		Keys:     keys,
+		Patterns: patterns,
+		Mask:     cfg.Mask,
+	})
+}
+
+// minOutputLevel returns the lowest level of the outputs. Outputs without a parsable level use defaultLevel.
+func minOutputLevel(outputs []config.OutputConfig, defaultLevel logging.Level) logging.Level {
+	lowest := logging.Level(math.MaxInt)
+	for _, out := range outputs {
+		lvl := defaultLevel
+		if l, err := logging.ParseLevel(out.Level); out.Level != "" && err == nil {
+			lvl = l
+		}
+		lowest = min(lowest, lvl)
+	}
+	return lowest
+}
+
+// newFanoutDriver creates a driver for each output. Outputs without a parsable level use defaultLevel.
+func newFanoutDriver(outputs []config.OutputConfig, defaultLevel logging.Level) *fanout.Driver {
+	fanoutOutputs := make([]fanout.Output, 0, len(outputs))
+	for _, out := range outputs {
+		lvl := defaultLevel
+		if out.Level != "" {
+			if l, err := logging.ParseLevel(out.Level); err == nil {
+				lvl = l
+			}
+		}
+		fanoutOutputs = append(fanoutOutputs, fanout.Output{
+			Driver:  newDriver(out),
+			Level:   lvl,
+			Include: out.IncludeAttributes,
+			Exclude: out.ExcludeAttributes,
+		})
+	}
+	return fanout.NewDriver(fanoutOutputs...)
+}
+
+// newDriver creates the driver for the output processing type, defaulting to plain text.
+func newDriver(out config.OutputConfig) Driver {
+	if out.ProcessingType == "otlp" {
+		otlpDriver, err := otlp.NewDriver(out.OTLP)
+		if err == nil {
+			return otlpDriver
+		}
+		fmt.Println("create otlp driver", err)
+	}
+	output, closer := openOutput(out.OutputFile, out.Rotation)
+	var driver Driver
+	switch out.ProcessingType {
+	case "json":
+		driver = json.NewDriver(output)
+	default:
+		driver = text.NewDriver(output)
+	}
+	if closer == nil {
+		return driver
+	}
+	return &outputDriver{Driver: driver, output: closer}
+}
+
+// outputDriver releases the output opened for the driver when closed.
+type outputDriver struct {
+	Driver
+	output io.Closer
+}
+
+func (d *outputDriver) Close(ctx context.Context) error {
+	return d.output.Close()
+}
+
+// closerFunc adapts a function to the io.Closer interface.
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// openOutput opens the output file, falling back to stdout. The returned closer releases the file, nil for stdout.
+func openOutput(file string, rotation *config.RotationConfig) (io.Writer, io.Closer) {
+	if file == "" {
+		return os.Stdout, nil
+	}
+	if rotation != nil {
+		w, err := rotate.NewWriter(rotate.Options{
+			Filename:   file,
+			MaxSize:    int64(rotation.MaxSizeMB) * 1024 * 1024,
+			MaxAge:     rotation.MaxAge,
+			MaxBackups: rotation.MaxBackups,
+			Compress:   rotation.Compress,
+			LocalTime:  rotation.LocalTime,
+		})
+		if err != nil {
+			fmt.Println("open rotating file", err)
+			return os.Stdout, nil
+		}
+		if rotation.ReopenOnSIGHUP {
+			stop := w.ReopenOnSignal()
+			return w, closerFunc(func() error {
+				stop()
+				return w.Close()
+			})
+		}
+		return w, w
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Println("open file", err)
+		return os.Stdout, nil
+	}
+	return f, f
+}
+
+// Close closes the driver of the logger if it supports closing, releasing the files, background workers and exporters
+// created by NewLoggerFromConfig. The driver is shared with the loggers derived from l, none of which must be used
+// afterward.
+func (l *Logger) Close(ctx context.Context) error {
+	switch d := l.driver.(type) {
+	case interface{ Close(context.Context) error }:
+		return d.Close(ctx)
+	case io.Closer:
+		return d.Close()
+	}
+	return nil
+}
+
 func (l *Logger) SetDefault() {
 	defaultLogger.Store(l)
 }
@@ -106,76 +373,315 @@ func Default() *Logger {
 }
 
 func (l *Logger) Log(ctx context.Context, level logging.Level, msg string, args ...any) {
-	if level < l.level {
-		return
+	l.log(ctx, level, msg, args...)
+}
+
+// LogAttrs is a faster version of Log, taking typed attributes instead of key-value pairs.
+func (l *Logger) LogAttrs(ctx context.Context, level logging.Level, msg string, attrs ...logging.Attr) {
+	l.logAttrs(ctx, level, msg, attrs...)
+}
+
+// maxPooledAttrs bounds the capacity of the pooled attribute slices, so a few large entries do not keep memory.
+const maxPooledAttrs = 64
+
+// attrPool holds the attribute slices of the entries, reused once the driver returns.
+var attrPool = sync.Pool{
+	New: func() any {
+		attrs := make([]logging.Attr, 0, 16)
+		return &attrs
+	},
+}
+
+// enabled reports whether an entry of the given level is logged, considering the transaction found in ctx.
+func (l *Logger) enabled(ctx context.Context, level logging.Level) bool {
+	if level < l.level.Level() {
+		return false
 	}
-	entry := logging.Entry{
-		Time:    time.Now(),
-		Message: msg,
-		Level:   level,
+	if l.unsampledLevel != nil && level < *l.unsampledLevel {
+		if sampled, ok := transaction.SampledFromContext(ctx); ok && !sampled {
+			return false
+		}
 	}
-	entry.Attrs = l.attrs
-	tx := transaction.FromContext(ctx)
-	entry.TraceID = tx.TraceID
-	entry.TransactionAttrs = tx.Attrs
+	return true
+}
+
+// log must be called directly by the exported logging methods and functions,
+// so the call site is always found at the same depth of the stack.
+func (l *Logger) log(ctx context.Context, level logging.Level, msg string, args ...any) {
+	if !l.enabled(ctx, level) {
+		return
+	}
+	attrs := attrPool.Get().(*[]logging.Attr)
+	*attrs = append(*attrs, l.attrs...)
 	for i := 0; i < len(args); i += 2 {
 		if key, ok := args[i].(string); ok && i+1 < len(args) {
-			entry.Attrs = append(entry.Attrs, logging.NewAttr(key, args[i+1]))
+			*attrs = append(*attrs, logging.NewAttr(key, args[i+1]))
 			continue
 		}
-		entry.Attrs = append(entry.Attrs, logging.NewAttr("undefKey", args[i]))
+		*attrs = append(*attrs, logging.NewAttr("undefKey", args[i]))
 		// move i backwards since we only processed one arg
 		i--
 	}
+	if len(l.groups) > 0 {
+		*attrs = append((*attrs)[:len(l.attrs)], l.nestInGroups((*attrs)[len(l.attrs):])...)
+	}
+	l.write(ctx, level, msg, attrs)
+}
+
+// logAttrs must be called directly by the exported logging methods and functions, like log.
+func (l *Logger) logAttrs(ctx context.Context, level logging.Level, msg string, attrs ...logging.Attr) {
+	if !l.enabled(ctx, level) {
+		return
+	}
+	entryAttrs := attrPool.Get().(*[]logging.Attr)
+	*entryAttrs = append(*entryAttrs, l.attrs...)
+	if len(l.groups) > 0 {
+		attrs = l.nestInGroups(attrs)
+	}
+	*entryAttrs = append(*entryAttrs, attrs...)
+	l.write(ctx, level, msg, entryAttrs)
+}
+
+// write passes the entry to the driver and puts attrs back in the pool, since drivers clone the entries they keep.
+// It must be called directly by log or logAttrs.
+func (l *Logger) write(ctx context.Context, level logging.Level, msg string, attrs *[]logging.Attr) {
+	var pc uintptr
+	if l.addSource {
+		var pcs [1]uintptr
+		// skip runtime.Callers, write, log and its exported caller
+		runtime.Callers(4, pcs[:])
+		pc = pcs[0]
+	}
+	entry := l.newEntry(ctx, time.Now(), level, msg, pc)
+	// resolve once for all the outputs, now that the entry is known to be emitted
+	entry.Attrs = logging.ResolveAttrs(*attrs)
+	l.redact(&entry)
+	// skip write, log and its exported caller
+	l.addStack(&entry, 3, 0)
 	l.driver.Log(ctx, entry)
+	if cap(*attrs) <= maxPooledAttrs {
+		// drop the references to the values before pooling
+		clear(*attrs)
+		*attrs = (*attrs)[:0]
+		attrPool.Put(attrs)
+	}
+}
+
+// redact masks the sensitive data of the resolved entry attrs. The shared transaction attrs are copied if redacted.
+func (l *Logger) redact(entry *logging.Entry) {
+	if l.redactor == nil {
+		return
+	}
+	entry.Attrs = redact.Attrs(l.redactor, entry.Attrs)
+	entry.TransactionAttrs = redact.Attrs(l.redactor, logging.ResolveAttrs(entry.TransactionAttrs))
+}
+
+// addStack sets the stack trace of the entries at or above the stack level of the logger. The stack trace carried by
+// an error attribute is preferred, otherwise the call stack is captured, skipping the caller of addStack and the
+// skip-1 frames above it. If the frame of the pc from is found, the stack starts there.
+func (l *Logger) addStack(entry *logging.Entry, skip int, from uintptr) {
+	if l.stackLevel == nil || entry.Level < *l.stackLevel {
+		return
+	}
+	if entry.Stack = stackFromAttrs(entry.Attrs); entry.Stack != nil {
+		return
+	}
+	var pcs [64]uintptr
+	// skip runtime.Callers and addStack too
+	frames := pcs[:runtime.Callers(skip+2, pcs[:])]
+	if i := slices.Index(frames, from); from != 0 && i >= 0 {
+		frames = frames[i:]
+	}
+	entry.Stack = logging.NewStack(frames)
+}
+
+// stackFromAttrs returns the stack trace carried by the first error attribute having one.
+func stackFromAttrs(attrs []logging.Attr) []logging.Source {
+	for _, attr := range attrs {
+		if attr.Value.Kind() != logging.KindAny {
+			continue
+		}
+		if err, ok := attr.Value.Any().(error); ok {
+			if stack := logging.StackFromError(err); stack != nil {
+				return stack
+			}
+		}
+	}
+	return nil
+}
+
+// newEntry creates an entry holding the details of the transaction found in ctx, leaving the attrs to the caller.
+// The source is resolved from pc when enabled on the logger.
+func (l *Logger) newEntry(ctx context.Context, t time.Time, level logging.Level, msg string, pc uintptr) logging.Entry {
+	tx := transaction.FromContext(ctx)
+	var source *logging.Source
+	if l.addSource && pc != 0 {
+		source = logging.NewSource(pc)
+	}
+	return logging.Entry{
+		Time:             t,
+		Message:          msg,
+		Level:            level,
+		Logger:           l.name,
+		Source:           source,
+		TraceID:          tx.TraceID,
+		SpanID:           transaction.SpanFromContext(ctx).ID,
+		TransactionAttrs: transaction.AttrsFromContext(ctx),
+	}
 }
 
 // WithAttrs creates a copy of the receiver logger and sets an attribute list to be logged for each message.
+// The attrs are added to the innermost group opened with WithGroup, if any.
 func (l *Logger) WithAttrs(attrs ...logging.Attr) *Logger {
 	clone := *l
-	clone.attrs = append(clone.attrs, attrs...)
+	if len(l.groups) == 0 {
+		clone.attrs = append(slices.Clip(l.attrs), attrs...)
+		return &clone
+	}
+	clone.groups = slices.Clone(l.groups)
+	last := &clone.groups[len(clone.groups)-1]
+	last.attrs = append(slices.Clip(last.attrs), attrs...)
+	return &clone
+}
+
+// WithGroup returns a copy of the logger that nests the attrs added afterward, by WithAttrs or by the logging
+// calls, in a group with the given name. Groups left without attrs are omitted. An empty name returns the receiver.
+func (l *Logger) WithGroup(name string) *Logger {
+	if name == "" {
+		return l
+	}
+	clone := *l
+	clone.groups = append(slices.Clip(l.groups), group{name: name})
 	return &clone
 }
 
+// nestInGroups nests attrs in the groups opened with WithGroup, along with the attrs added to each of them.
+// The result never shares memory with attrs.
+func (l *Logger) nestInGroups(attrs []logging.Attr) []logging.Attr {
+	for i := len(l.groups) - 1; i >= 0; i-- {
+		members := append(slices.Clip(l.groups[i].attrs), attrs...)
+		if len(members) == 0 {
+			attrs = nil
+			continue
+		}
+		attrs = []logging.Attr{logging.Group(l.groups[i].name, members...)}
+	}
+	return attrs
+}
+
+// Named returns a copy of the logger whose name is the receiver name followed by a dot and the given name.
+// The copy uses the level registered for the longest prefix of its name (see RegisterLevel and config.LogConfig
+// Levels), or shares the receiver level if none matches.
+func (l *Logger) Named(name string) *Logger {
+	clone := *l
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	clone.name = name
+	if level, ok := matchingLevel(name); ok {
+		clone.level = level
+	}
+	return &clone
+}
+
+// Name returns the name of the logger, empty for unnamed loggers.
+func (l *Logger) Name() string {
+	return l.name
+}
+
 // WithLevel returns a copy of the original logger with the desired log-level set, if parsable.
+// The copy gets its own level variable, shared with its WithAttrs clones: it overrides the level of the original
+// logger, so neither changes its level nor follows the later SetLevel calls or LevelHandler updates of the original.
 func (l *Logger) WithLevel(level string) (*Logger, error) {
 	lvl, err := logging.ParseLevel(level)
 	if err != nil {
 		return nil, err
 	}
 	clone := *l
-	clone.level = lvl
+	clone.level = logging.NewLevelVar(lvl)
 	return &clone, nil
 }
 
+// WithSource returns a copy of the logger that adds the file, line and function of the call site to each entry.
+func (l *Logger) WithSource(enabled bool) *Logger {
+	clone := *l
+	clone.addSource = enabled
+	return &clone
+}
+
+// WithStackTrace returns a copy of the logger that adds a stack trace to the entries at or above level.
+// The stack trace carried by an error attribute is preferred to the one of the logging call.
+func (l *Logger) WithStackTrace(level logging.Level) *Logger {
+	clone := *l
+	clone.stackLevel = &level
+	return &clone
+}
+
+// WithUnsampledLevel returns a copy of the logger that drops the entries below level logged within transactions whose
+// trace is not sampled. Entries logged outside transactions are not affected.
+func (l *Logger) WithUnsampledLevel(level logging.Level) *Logger {
+	clone := *l
+	clone.unsampledLevel = &level
+	return &clone
+}
+
+// WithRedactor returns a copy of the logger that masks the sensitive data of the entry and transaction attrs with r,
+// before the driver sees them. A nil r disables redaction.
+func (l *Logger) WithRedactor(r redact.Redactor) *Logger {
+	clone := *l
+	clone.redactor = r
+	return &clone
+}
+
+// Level returns the current level of the logger.
+func (l *Logger) Level() logging.Level {
+	return l.level.Level()
+}
+
+// SetLevel changes the level of the logger and of all the clones sharing its level variable, i.e. those made with
+// WithAttrs, WithGroup and the like. Clones made with WithLevel have their own level variable.
+func (l *Logger) SetLevel(level logging.Level) {
+	l.level.Set(level)
+}
+
 func (l *Logger) Debug(ctx context.Context, msg string, args ...any) {
-	l.Log(ctx, logging.LevelDebug, msg, args...)
+	l.log(ctx, logging.LevelDebug, msg, args...)
 }
 
 func (l *Logger) Info(ctx context.Context, msg string, args ...any) {
-	l.Log(ctx, logging.LevelInfo, msg, args...)
+	l.log(ctx, logging.LevelInfo, msg, args...)
 }
 
 func (l *Logger) Warn(ctx context.Context, msg string, args ...any) {
-	l.Log(ctx, logging.LevelWarn, msg, args...)
+	l.log(ctx, logging.LevelWarn, msg, args...)
 }
 
 func (l *Logger) Error(ctx context.Context, msg string, args ...any) {
-	l.Log(ctx, logging.LevelError, msg, args...)
+	l.log(ctx, logging.LevelError, msg, args...)
+}
+
+// LogAttrs logs through the default logger, taking typed attributes instead of key-value pairs.
+func LogAttrs(ctx context.Context, level logging.Level, msg string, attrs ...logging.Attr) {
+	Default().logAttrs(ctx, level, msg, attrs...)
+}
+
+// Named returns a named copy of the default logger.
+func Named(name string) *Logger {
+	return Default().Named(name)
 }
 
 func Debug(ctx context.Context, msg string, args ...any) {
-	Default().Log(ctx, logging.LevelDebug, msg, args...)
+	Default().log(ctx, logging.LevelDebug, msg, args...)
 }
 
 func Info(ctx context.Context, msg string, args ...any) {
-	Default().Log(ctx, logging.LevelInfo, msg, args...)
+	Default().log(ctx, logging.LevelInfo, msg, args...)
 }
 
 func Warn(ctx context.Context, msg string, args ...any) {
-	Default().Log(ctx, logging.LevelWarn, msg, args...)
+	Default().log(ctx, logging.LevelWarn, msg, args...)
 }
 
 func Error(ctx context.Context, msg string, args ...any) {
-	Default().Log(ctx, logging.LevelError, msg, args...)
+	Default().log(ctx, logging.LevelError, msg, args...)
 }
@@ -0,0 +1,51 @@
+package log_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/silvan-talos/tlp/json"
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/text"
+)
+
+func benchmarkDrivers() map[string]log.Driver {
+	return map[string]log.Driver{
+		"text": text.NewDriver(io.Discard),
+		"json": json.NewDriver(io.Discard),
+	}
+}
+
+func BenchmarkLogger_Log(b *testing.B) {
+	ctx := context.Background()
+	for name, driver := range benchmarkDrivers() {
+		logger := log.NewLogger(driver, logging.LevelInfo).WithAttrs(logging.String("env", "bench"))
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.Info(ctx, "request completed", "path", "/users/12", "status", 200, "cached", true)
+			}
+		})
+	}
+}
+
+func BenchmarkLogger_LogAttrs(b *testing.B) {
+	ctx := context.Background()
+	for name, driver := range benchmarkDrivers() {
+		logger := log.NewLogger(driver, logging.LevelInfo).WithAttrs(logging.String("env", "bench"))
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				logger.LogAttrs(ctx, logging.LevelInfo, "request completed",
+					logging.String("path", "/users/12"),
+					logging.Int("status", 200),
+					logging.Duration("elapsed", 1500*time.Microsecond),
+					logging.Float64("ratio", 0.25),
+				)
+			}
+		})
+	}
+}
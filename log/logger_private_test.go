@@ -1,6 +1,8 @@
 package log
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -23,20 +25,20 @@ func TestLogger_WithAttrs(t *testing.T) {
 			attrs: []logging.Attr{
 				{
 					Key:   "env",
-					Value: "test",
+					Value: logging.StringValue("test"),
 				},
 				{
 					Key:   "test-type",
-					Value: "unit",
+					Value: logging.StringValue("unit"),
 				},
 			},
 			expected: []logging.Attr{{
 				Key:   "env",
-				Value: "test",
+				Value: logging.StringValue("test"),
 			},
 				{
 					Key:   "test-type",
-					Value: "unit",
+					Value: logging.StringValue("unit"),
 				},
 			},
 		},
@@ -74,9 +76,27 @@ func TestLogger_WithLevel(t *testing.T) {
 		logger, err := Default().WithLevel(tc.level)
 		if !tc.shouldError {
 			require.NoError(t, err, "test case shouldn't error")
-			require.Equal(t, tc.want, logger.level, "logger should have the correct level")
+			require.Equal(t, tc.want, logger.level.Level(), "logger should have the correct level")
 		} else {
 			require.Error(t, err, "test case should return error")
 		}
 	}
 }
+
+func TestShutdown(t *testing.T) {
+	var calls int
+	addShutdownHook(func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	addShutdownHook(func(ctx context.Context) error {
+		return errors.New("flush failed")
+	})
+
+	err := Shutdown(context.Background())
+	require.EqualError(t, err, "flush failed", "hook errors should be reported")
+	require.Equal(t, 1, calls, "every hook should be called")
+
+	require.NoError(t, Shutdown(context.Background()))
+	require.Equal(t, 1, calls, "hooks should be called once")
+}
@@ -2,13 +2,27 @@ package log_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/dummy"
 	"github.com/silvan-talos/tlp/log"
 	"github.com/silvan-talos/tlp/logging"
 	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/redact"
 	"github.com/silvan-talos/tlp/transaction"
 )
 
@@ -17,6 +31,7 @@ func TestLogger_Log(t *testing.T) {
 	t.Run("logger with attrs", checkLoggerAttributes)
 	t.Run("logger with args and transaction details", checkLoggerArgsAndTransaction)
 	t.Run("arg with undefined key", argWithUndefinedKey)
+	t.Run("entry inside span", entryInsideSpan)
 }
 
 func checkLevelFunctionality(t *testing.T) {
@@ -144,3 +159,392 @@ func argWithUndefinedKey(t *testing.T) {
 	logger := log.NewLogger(driver, logging.LevelDebug)
 	logger.Log(context.Background(), logging.LevelInfo, "test message to be logged", "reason", "test", 3, "string test")
 }
+
+func entryInsideSpan(t *testing.T) {
+	t.Parallel()
+
+	var spanID string
+	driver := &mock.Driver{
+		LogFn: func(ctx context.Context, entry logging.Entry) {
+			require.Equal(t, "test-trace", entry.TraceID, "trace ID should be passed")
+			require.Equal(t, spanID, entry.SpanID, "span ID should be passed")
+		},
+	}
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	tx, ctx := tracer.StartTransaction(context.Background(), "test", "span-test")
+	defer tx.End()
+	span, ctx := transaction.StartSpan(ctx, "query", "db")
+	defer span.End()
+	spanID = span.ID
+	logger := log.NewLogger(driver, logging.LevelDebug)
+	logger.Log(ctx, logging.LevelInfo, "test message to be logged")
+	require.Equal(t, 1, driver.Count, "entry should be logged")
+}
+
+func TestNewLoggerFromConfig_Outputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	textFile, jsonFile := filepath.Join(dir, "debug.log"), filepath.Join(dir, "info.json")
+	logger := log.NewLoggerFromConfig(config.LogConfig{
+		Outputs: []config.OutputConfig{
+			{Level: "debug", ProcessingType: "plain", OutputFile: textFile},
+			{Level: "info", ProcessingType: "json", OutputFile: jsonFile, ExcludeAttributes: []string{"query"}},
+		},
+	})
+	logger.Debug(context.Background(), "running query", "query", "select")
+	logger.Info(context.Background(), "user created", "id", 12, "query", "insert")
+
+	textOut, err := os.ReadFile(textFile)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(textOut)), "\n"), 2, "debug output should receive both entries")
+	require.Contains(t, string(textOut), "DEBUG: running query")
+	jsonOut, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(jsonOut)), "\n"), 1, "info output should only receive the info entry")
+	require.Contains(t, string(jsonOut), `"Message":"user created"`)
+	require.NotContains(t, string(jsonOut), "insert", "excluded attrs should be filtered out")
+}
+
+func TestNewLoggerFromConfig_ExampleOutputs(t *testing.T) {
+	t.Parallel()
+
+	var cfg config.Config
+	require.NoError(t, config.LoadFromYAML("../config/config_example.yml", &cfg))
+	dir := t.TempDir()
+	for i := range cfg.Log.Outputs {
+		cfg.Log.Outputs[i].OutputFile = filepath.Join(dir, fmt.Sprintf("output-%d.log", i))
+	}
+	logger := log.NewLoggerFromConfig(cfg.Log)
+	logger.Debug(context.Background(), "debug entry")
+	logger.Info(context.Background(), "info entry")
+
+	debugOut, err := os.ReadFile(cfg.Log.Outputs[0].OutputFile)
+	require.NoError(t, err)
+	require.Contains(t, string(debugOut), "DEBUG: debug entry", "debug output should receive debug entries")
+	require.Contains(t, string(debugOut), "INFO: info entry")
+	infoOut, err := os.ReadFile(cfg.Log.Outputs[1].OutputFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(infoOut), "debug entry", "info output should filter debug entries")
+	require.Contains(t, string(infoOut), `"Message":"info entry"`)
+}
+
+func TestNewLoggerFromConfig_Redaction(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	logger := log.NewLoggerFromConfig(config.LogConfig{
+		OutputFile: file,
+		Redaction:  &config.RedactionConfig{Patterns: []string{"(", "email"}},
+	})
+	logger.Info(context.Background(), "user created", "email", "ann@example.com")
+
+	out, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "email='[REDACTED]'", "valid patterns should apply despite an invalid one")
+}
+
+func TestNewLoggerFromConfig_Sampling(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	logger := log.NewLoggerFromConfig(config.LogConfig{
+		OutputFile: file,
+		Sampling:   &config.SamplingConfig{Interval: time.Hour, First: 2},
+	})
+	// the default recorder samples every trace
+	tx, ctx := transaction.NewTracer(dummy.NewRecorder()).StartTransaction(context.Background(), "test", "sampling-test")
+	defer tx.End()
+	require.True(t, tx.Sampled())
+	for i := 0; i < 10; i++ {
+		logger.Warn(ctx, "retrying", "attempt", i)
+	}
+	require.NoError(t, logger.Close(context.Background()), "closing should stop the summary ticker")
+
+	out, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	require.Len(t, lines, 3, "only the first entries and the summary should be logged")
+	require.Contains(t, lines[2], "log entries suppressed")
+	require.Contains(t, lines[2], "suppressed='8'")
+}
+
+func TestLogger_Named(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	textFile, jsonFile := filepath.Join(dir, "app.log"), filepath.Join(dir, "app.json")
+	logger := log.NewLoggerFromConfig(config.LogConfig{
+		Level:  "info",
+		Levels: map[string]string{"named-test-db": "warn", "named-test-http": "debug"},
+		Outputs: []config.OutputConfig{
+			{ProcessingType: "plain", OutputFile: textFile},
+			{ProcessingType: "json", OutputFile: jsonFile},
+		},
+	})
+	db, mysql := logger.Named("named-test-db"), logger.Named("named-test-db").Named("mysql")
+	require.Equal(t, "named-test-db.mysql", mysql.Name(), "names should be dot-separated")
+	require.Equal(t, logging.LevelWarn, mysql.Level(), "level of the longest prefix should be used")
+	require.Equal(t, logging.LevelDebug, logger.Named("named-test-http").Level())
+	require.Equal(t, logging.LevelInfo, logger.Named("named-test-cache").Level(), "logger level should be used without a match")
+
+	ctx := context.Background()
+	db.Info(ctx, "db info")
+	mysql.Warn(ctx, "mysql warn")
+	logger.Named("named-test-http").Debug(ctx, "http debug")
+	logger.Debug(ctx, "root debug")
+
+	textOut, err := os.ReadFile(textFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(textOut), "db info", "db logs below warn should be skipped")
+	require.NotContains(t, string(textOut), "root debug", "root logs below info should be skipped")
+	require.Contains(t, string(textOut), "WARN: mysql warn\tlogger=named-test-db.mysql")
+	require.Contains(t, string(textOut), "DEBUG: http debug\tlogger=named-test-http")
+	jsonOut, err := os.ReadFile(jsonFile)
+	require.NoError(t, err)
+	require.Contains(t, string(jsonOut), `"Logger":"named-test-db.mysql"`)
+}
+
+// TestLogger_WithSource replaces the default logger, so it must not run in parallel with other tests.
+func TestLogger_WithSource(t *testing.T) {
+	original := log.Default()
+	t.Cleanup(original.SetDefault)
+
+	var source *logging.Source
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		source = entry.Source
+	}}
+	logger := log.NewLogger(driver, logging.LevelDebug).WithSource(true)
+	logger.SetDefault()
+	ctx := context.Background()
+
+	tests := map[string]func() int{
+		"logger method": func() int {
+			logger.Info(ctx, "test")
+			return callerLine()
+		},
+		"logger log": func() int {
+			logger.Log(ctx, logging.LevelWarn, "test")
+			return callerLine()
+		},
+		"package function": func() int {
+			log.Error(ctx, "test")
+			return callerLine()
+		},
+		"named logger": func() int {
+			logger.Named("source").Debug(ctx, "test")
+			return callerLine()
+		},
+	}
+	for name, logFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			source = nil
+			line := logFn() - 1
+			require.NotNil(t, source, "source should be set")
+			require.Equal(t, line, source.Line, "call site should be reported")
+			require.Equal(t, "logger_test.go", filepath.Base(source.File))
+			require.Contains(t, source.Function, "TestLogger_WithSource")
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		logger.WithSource(false).Info(ctx, "test")
+		require.Nil(t, source, "source should not be captured")
+	})
+}
+
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestLogger_WithStackTrace(t *testing.T) {
+	t.Parallel()
+
+	var stack []logging.Source
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		stack = entry.Stack
+	}}
+	logger := log.NewLogger(driver, logging.LevelDebug).WithStackTrace(logging.LevelError)
+	ctx := context.Background()
+
+	logger.Warn(ctx, "test")
+	require.Nil(t, stack, "entries below the stack trace level should not get a stack trace")
+
+	logger.Error(ctx, "test", "err", errors.New("plain"))
+	require.NotEmpty(t, stack, "stack trace should be captured")
+	require.Contains(t, stack[0].Function, "TestLogger_WithStackTrace", "logging call should be the innermost frame")
+}
+
+func TestLogger_WithGroup(t *testing.T) {
+	t.Parallel()
+
+	var attrs []logging.Attr
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		attrs = entry.Clone().Attrs
+	}}
+	logger := log.NewLogger(driver, logging.LevelInfo).WithAttrs(logging.String("env", "test"))
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		log      func()
+		expected []logging.Attr
+	}{
+		"call attrs": {
+			log: func() {
+				logger.WithGroup("http").Info(ctx, "request", "method", "GET", "status", 200)
+			},
+			expected: []logging.Attr{
+				logging.String("env", "test"),
+				logging.Group("http", logging.String("method", "GET"), logging.Int("status", 200)),
+			},
+		},
+		"nested groups with attrs": {
+			log: func() {
+				logger.WithGroup("http").WithAttrs(logging.String("method", "GET")).
+					WithGroup("response").LogAttrs(ctx, logging.LevelInfo, "request", logging.Int("status", 200))
+			},
+			expected: []logging.Attr{
+				logging.String("env", "test"),
+				logging.Group("http", logging.String("method", "GET"), logging.Group("response", logging.Int("status", 200))),
+			},
+		},
+		"empty groups omitted": {
+			log: func() {
+				logger.WithGroup("http").WithGroup("response").Info(ctx, "request")
+			},
+			expected: []logging.Attr{logging.String("env", "test")},
+		},
+		"empty name": {
+			log: func() {
+				logger.WithGroup("").Info(ctx, "request", "status", 200)
+			},
+			expected: []logging.Attr{logging.String("env", "test"), logging.Int("status", 200)},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc.log()
+			// group values hold pointers, compared by Equal rather than reflection
+			require.True(t, slices.EqualFunc(tc.expected, attrs, logging.Attr.Equal), "unexpected attrs: %v", attrs)
+		})
+	}
+}
+
+type countingValuer struct {
+	calls *int
+}
+
+func (v countingValuer) LogValue() logging.Value {
+	*v.calls++
+	return logging.StringValue("expensive")
+}
+
+func TestLogger_LogValuer(t *testing.T) {
+	t.Parallel()
+
+	var attrs []logging.Attr
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		attrs = entry.Clone().Attrs
+	}}
+	logger := log.NewLogger(driver, logging.LevelInfo)
+	var calls int
+	ctx := context.Background()
+
+	logger.Debug(ctx, "skipped", "diff", countingValuer{calls: &calls})
+	require.Zero(t, calls, "values of filtered entries should not be resolved")
+
+	logger.Info(ctx, "logged", "diff", countingValuer{calls: &calls})
+	require.Equal(t, 1, calls)
+	require.Equal(t, "expensive", attrs[0].Value.String(), "value should be resolved before reaching the driver")
+}
+
+func TestLogger_WithRedactor(t *testing.T) {
+	t.Parallel()
+
+	var entry logging.Entry
+	driver := &mock.Driver{LogFn: func(ctx context.Context, e logging.Entry) {
+		entry = e.Clone()
+	}}
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{
+		RecordTransactionFn: func(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context) {
+			return &transaction.Transaction{TraceID: "test-trace"}, ctx
+		},
+	})
+	tx, ctx := tracer.StartTransaction(context.Background(), "test", "redaction-test",
+		logging.String("authorization", "Bearer secret"))
+	defer tx.End()
+	logger := log.NewLogger(driver, logging.LevelInfo).WithRedactor(redact.New(redact.Options{
+		Keys:     []string{"password", "authorization"},
+		Patterns: []*regexp.Regexp{redact.EmailPattern},
+	}))
+
+	logger.WithGroup("user").Info(ctx, "login", "email", "ann@example.com", "password", "hunter2", "id", 1)
+
+	expected := []logging.Attr{logging.Group("user",
+		logging.String("email", redact.Mask),
+		logging.String("password", redact.Mask),
+		logging.Int("id", 1),
+	)}
+	require.True(t, slices.EqualFunc(expected, entry.Attrs, logging.Attr.Equal), "unexpected attrs: %v", entry.Attrs)
+	require.Equal(t, redact.Mask, entry.TransactionAttrs[0].Value.String(), "transaction attrs should be redacted")
+	require.Equal(t, "Bearer secret", tx.Attrs[0].Value.String(), "transaction attrs should not be modified")
+}
+
+func TestLogger_WithUnsampledLevel(t *testing.T) {
+	t.Parallel()
+
+	var messages []string
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		messages = append(messages, entry.Message)
+	}}
+	logger := log.NewLogger(driver, logging.LevelDebug).WithUnsampledLevel(logging.LevelWarn)
+	unsampled := (&transaction.Transaction{TraceID: "unsampled"}).NewContext(context.Background())
+	sampledTx := &transaction.Transaction{TraceID: "sampled"}
+	sampledTx.SetSampled(true)
+	sampled := sampledTx.NewContext(context.Background())
+
+	logger.Debug(unsampled, "unsampled debug")
+	logger.Info(unsampled, "unsampled info")
+	logger.Warn(unsampled, "unsampled warn")
+	logger.Debug(sampled, "sampled debug")
+	logger.Info(context.Background(), "no transaction")
+
+	require.Equal(t, []string{"unsampled warn", "sampled debug", "no transaction"}, messages)
+}
+
+// countingDriver counts the entries logged, unlike mock.Driver it can be used concurrently.
+type countingDriver struct {
+	logged atomic.Int64
+}
+
+func (d *countingDriver) Log(ctx context.Context, entry logging.Entry) {
+	d.logged.Add(1)
+}
+
+func TestLogger_ErrorSamplingConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	driver := &countingDriver{}
+	logger := log.NewLogger(driver, logging.LevelDebug).WithUnsampledLevel(logging.LevelWarn)
+	recorder := dummy.NewRecorder(dummy.WithSampler(transaction.NeverSample()), dummy.WithErrorSampling())
+	tx, ctx := transaction.NewTracer(recorder).StartTransaction(context.Background(), "test", "race-test")
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				logger.Debug(ctx, "processing", "goroutine", g, "item", i)
+			}
+		}()
+	}
+	tx.RecordError(errors.New("failed"))
+	wg.Wait()
+
+	require.True(t, tx.Sampled(), "transaction should be sampled once the error is recorded")
+	before := driver.logged.Load()
+	logger.Debug(ctx, "after error")
+	require.Equal(t, before+1, driver.logged.Load(), "entries logged after the error should be kept")
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+// Recover stops a panic, logs it through the default logger and marks the transaction found in ctx as failed.
+// It must be deferred directly:
+//
+//	defer log.Recover(ctx)
+func Recover(ctx context.Context) {
+	if v := recover(); v != nil {
+		Default().logPanic(ctx, v)
+	}
+}
+
+// Recover stops a panic, logs it and marks the transaction found in ctx as failed. It must be deferred directly:
+//
+//	defer logger.Recover(ctx)
+func (l *Logger) Recover(ctx context.Context) {
+	if v := recover(); v != nil {
+		l.logPanic(ctx, v)
+	}
+}
+
+// LogPanic logs a recovered panic value and marks the transaction found in ctx as failed, for callers that recover
+// on their own, for example to panic again afterward:
+//
+//	defer func() {
+//		if v := recover(); v != nil {
+//			logger.LogPanic(ctx, v)
+//			panic(v)
+//		}
+//	}()
+func (l *Logger) LogPanic(ctx context.Context, value any) {
+	l.logPanic(ctx, value)
+}
+
+// logPanic logs value at error level, along with the stack trace of the panic, regardless of the logger levels.
+// It must be called directly by the exported recovery functions, which must be called by the deferred function.
+func (l *Logger) logPanic(ctx context.Context, value any) {
+	err, ok := value.(error)
+	if !ok {
+		err = fmt.Errorf("panic: %v", value)
+	}
+	transaction.FromContext(ctx).RecordError(err)
+
+	var pcs [64]uintptr
+	// skip runtime.Callers and logPanic
+	n := runtime.Callers(2, pcs[:])
+	stack := panicStack(logging.NewStack(pcs[:n]))
+	entry := l.newEntry(ctx, time.Now(), logging.LevelError, "panic recovered", 0)
+	if l.addSource && len(stack) > 0 {
+		entry.Source = &stack[0]
+	}
+	entry.Attrs = logging.ResolveAttrs(append(slices.Clip(l.attrs), logging.NewAttr("panic", value)))
+	l.redact(&entry)
+	entry.Stack = stack
+	if errStack := logging.StackFromError(err); errStack != nil {
+		entry.Stack = errStack
+	}
+	l.driver.Log(ctx, entry)
+}
+
+// panicStack drops the frames of the recovery functions, so the stack starts where the panic happened.
+func panicStack(stack []logging.Source) []logging.Source {
+	for i, frame := range stack {
+		if frame.Function == "runtime.gopanic" {
+			return stack[i+1:]
+		}
+	}
+	return stack
+}
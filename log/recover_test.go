@@ -0,0 +1,60 @@
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestLogger_Recover(t *testing.T) {
+	t.Parallel()
+
+	var entry logging.Entry
+	driver := &mock.Driver{LogFn: func(ctx context.Context, e logging.Entry) {
+		entry = e.Clone()
+	}}
+	// the panic is logged even if the logger level is higher
+	logger := log.NewLogger(driver, logging.LevelError+4)
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	tx, ctx := tracer.StartTransaction(context.Background(), "test", "recover-test")
+
+	require.NotPanics(t, func() {
+		defer logger.Recover(ctx)
+		panicking()
+	}, "panic should be stopped")
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+	require.Equal(t, logging.LevelError, entry.Level)
+	require.Equal(t, "test-trace", entry.TraceID, "panic should be correlated to the transaction")
+	require.Contains(t, entry.Attrs, logging.NewAttr("panic", "boom"))
+	require.NotEmpty(t, entry.Stack, "stack trace should be logged")
+	require.Contains(t, entry.Stack[0].Function, "panicking", "stack trace should start where the panic happened")
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome(), "transaction should be marked as failed")
+	require.Len(t, tx.Errors(), 1)
+}
+
+func TestLogger_LogPanic(t *testing.T) {
+	t.Parallel()
+
+	driver := &mock.Driver{}
+	logger := log.NewLogger(driver, logging.LevelInfo)
+	require.PanicsWithValue(t, "boom", func() {
+		defer func() {
+			if v := recover(); v != nil {
+				logger.LogPanic(context.Background(), v)
+				panic(v)
+			}
+		}()
+		panicking()
+	}, "panic should be propagated")
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+}
+
+func panicking() {
+	panic("boom")
+}
@@ -0,0 +1,193 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// SlogHandler exposes a Logger as an slog.Handler, so records of libraries logging through log/slog reach the
+// logger driver, correlated with the transaction found in the context.
+// slog groups are mapped to group attributes and WithGroup to Logger.WithGroup.
+type SlogHandler struct {
+	logger *Logger
+}
+
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{
+		logger: logger,
+	}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.enabled(ctx, logging.Level(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	entry := h.logger.newEntry(ctx, r.Time, logging.Level(r.Level), r.Message, r.PC)
+	attrs := make([]logging.Attr, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, attr)
+		return true
+	})
+	if len(h.logger.groups) > 0 {
+		attrs = h.logger.nestInGroups(attrs)
+	}
+	// clip the logger attrs, so appending never writes to their shared backing array
+	entry.Attrs = logging.ResolveAttrs(append(slices.Clip(h.logger.attrs), attrs...))
+	h.logger.redact(&entry)
+	// the stack starts at the call site of the record, above the slog frames
+	h.logger.addStack(&entry, 1, r.PC)
+	h.logger.driver.Log(ctx, entry)
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var converted []logging.Attr
+	for _, attr := range attrs {
+		converted = appendSlogAttr(converted, attr)
+	}
+	return &SlogHandler{
+		logger: h.logger.WithAttrs(converted...),
+	}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{
+		logger: h.logger.WithGroup(name),
+	}
+}
+
+// appendSlogAttr converts attr and appends the result to attrs, following the slog.Handler rules:
+// empty attrs and groups are ignored and the members of groups without a key are inlined.
+func appendSlogAttr(attrs []logging.Attr, attr slog.Attr) []logging.Attr {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return attrs
+	}
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(attrs, logging.Attr{Key: attr.Key, Value: fromSlogValue(attr.Value)})
+	}
+	var members []logging.Attr
+	for _, member := range attr.Value.Group() {
+		members = appendSlogAttr(members, member)
+	}
+	switch {
+	case len(members) == 0:
+		return attrs
+	case attr.Key == "":
+		return append(attrs, members...)
+	default:
+		return append(attrs, logging.Group(attr.Key, members...))
+	}
+}
+
+// SlogDriver is a driver that hands the entries over to an slog.Handler.
+// The logger name is passed as the `logger` attribute, the source as the slog.SourceKey attribute and the stack trace
+// as the `stack` attribute. Trace details are passed as `traceID` and `spanID` attributes, transaction attrs as the
+// `transaction` group.
+type SlogDriver struct {
+	handler slog.Handler
+}
+
+func NewSlogDriver(handler slog.Handler) *SlogDriver {
+	return &SlogDriver{
+		handler: handler,
+	}
+}
+
+func (d *SlogDriver) Log(ctx context.Context, entry logging.Entry) {
+	level := slog.Level(entry.Level)
+	if !d.handler.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(entry.Time, level, entry.Message, 0)
+	if entry.Logger != "" {
+		r.AddAttrs(slog.String("logger", entry.Logger))
+	}
+	if entry.Source != nil {
+		r.AddAttrs(slog.Any(slog.SourceKey, slogSource(*entry.Source)))
+	}
+	for _, attr := range logging.ResolveAttrs(entry.Attrs) {
+		r.AddAttrs(slog.Attr{Key: attr.Key, Value: toSlogValue(attr.Value)})
+	}
+	if entry.TraceID != "" {
+		r.AddAttrs(slog.String("traceID", entry.TraceID))
+	}
+	if entry.SpanID != "" {
+		r.AddAttrs(slog.String("spanID", entry.SpanID))
+	}
+	if len(entry.TransactionAttrs) > 0 {
+		txAttrs := make([]any, len(entry.TransactionAttrs))
+		for i, attr := range logging.ResolveAttrs(entry.TransactionAttrs) {
+			txAttrs[i] = slog.Attr{Key: attr.Key, Value: toSlogValue(attr.Value)}
+		}
+		r.AddAttrs(slog.Group("transaction", txAttrs...))
+	}
+	if len(entry.Stack) > 0 {
+		stack := make([]*slog.Source, len(entry.Stack))
+		for i, frame := range entry.Stack {
+			stack[i] = slogSource(frame)
+		}
+		r.AddAttrs(slog.Any("stack", stack))
+	}
+	_ = d.handler.Handle(ctx, r)
+}
+
+func slogSource(source logging.Source) *slog.Source {
+	return &slog.Source{Function: source.Function, File: source.File, Line: source.Line}
+}
+
+// fromSlogValue converts a resolved slog value, other than a group.
+func fromSlogValue(v slog.Value) logging.Value {
+	switch v.Kind() {
+	case slog.KindString:
+		return logging.StringValue(v.String())
+	case slog.KindInt64:
+		return logging.Int64Value(v.Int64())
+	case slog.KindUint64:
+		return logging.Uint64Value(v.Uint64())
+	case slog.KindFloat64:
+		return logging.Float64Value(v.Float64())
+	case slog.KindBool:
+		return logging.BoolValue(v.Bool())
+	case slog.KindDuration:
+		return logging.DurationValue(v.Duration())
+	case slog.KindTime:
+		return logging.TimeValue(v.Time())
+	default:
+		return logging.AnyValue(v.Any())
+	}
+}
+
+func toSlogValue(v logging.Value) slog.Value {
+	switch v.Kind() {
+	case logging.KindString:
+		return slog.StringValue(v.String())
+	case logging.KindInt64:
+		return slog.Int64Value(v.Int64())
+	case logging.KindUint64:
+		return slog.Uint64Value(v.Uint64())
+	case logging.KindFloat64:
+		return slog.Float64Value(v.Float64())
+	case logging.KindBool:
+		return slog.BoolValue(v.Bool())
+	case logging.KindDuration:
+		return slog.DurationValue(v.Duration())
+	case logging.KindTime:
+		return slog.TimeValue(v.Time())
+	case logging.KindGroup:
+		members := make([]slog.Attr, 0, len(v.Group()))
+		for _, attr := range v.Group() {
+			members = append(members, slog.Attr{Key: attr.Key, Value: toSlogValue(attr.Value)})
+		}
+		return slog.GroupValue(members...)
+	default:
+		return slog.AnyValue(v.Any())
+	}
+}
@@ -0,0 +1,154 @@
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestSlogHandler(t *testing.T) {
+	t.Run("levels", slogHandlerLevels)
+	t.Run("record with transaction", slogHandlerRecordWithTransaction)
+	t.Run("attrs and groups", slogHandlerAttrsAndGroups)
+	t.Run("stack trace", slogHandlerStackTrace)
+}
+
+func slogHandlerLevels(t *testing.T) {
+	t.Parallel()
+
+	driver := &mock.Driver{}
+	logger := slog.New(log.NewSlogHandler(log.NewLogger(driver, logging.LevelWarn)))
+	logger.Info("skipped")
+	logger.Warn("logged")
+	logger.Log(context.Background(), slog.Level(logging.LevelError), "logged")
+	require.Equal(t, 2, driver.Count, "levels should be mapped to tlp levels")
+}
+
+func slogHandlerRecordWithTransaction(t *testing.T) {
+	t.Parallel()
+
+	driver := &mock.Driver{
+		LogFn: func(ctx context.Context, entry logging.Entry) {
+			require.Equal(t, "query executed", entry.Message)
+			require.Equal(t, logging.LevelDebug, entry.Level)
+			require.Equal(t, "test-trace", entry.TraceID, "entry should be correlated to the transaction")
+			require.Contains(t, entry.TransactionAttrs, logging.NewAttr("name", "test"))
+			require.Equal(t, []logging.Attr{logging.NewAttr("rows", int64(3))}, entry.Attrs)
+		},
+	}
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	tx, ctx := tracer.StartTransaction(context.Background(), "test", "slog-test")
+	defer tx.End()
+	logger := slog.New(log.NewSlogHandler(log.NewLogger(driver, logging.LevelDebug)))
+	logger.DebugContext(ctx, "query executed", "rows", 3)
+	require.Equal(t, 1, driver.Count, "entry should be logged")
+}
+
+func slogHandlerAttrsAndGroups(t *testing.T) {
+	t.Parallel()
+
+	driver := &mock.Driver{
+		LogFn: func(ctx context.Context, entry logging.Entry) {
+			expected := []logging.Attr{
+				logging.NewAttr("component", "db"),
+				logging.Group("sql",
+					logging.NewAttr("driver", "mysql"),
+					logging.Group("query",
+						logging.NewAttr("table", "users"),
+						logging.NewAttr("rows", int64(3)),
+					),
+				),
+			}
+			// group values hold pointers, compared by Equal rather than reflection
+			require.True(t, slices.EqualFunc(expected, entry.Attrs, logging.Attr.Equal), "unexpected attrs: %v", entry.Attrs)
+		},
+	}
+	logger := slog.New(log.NewSlogHandler(log.NewLogger(driver, logging.LevelDebug))).
+		With("component", "db").
+		WithGroup("sql").
+		With("driver", "mysql")
+	logger.Info("query executed", slog.Group("query", "table", "users", "rows", 3), slog.Group("empty"))
+	require.Equal(t, 1, driver.Count, "entry should be logged")
+}
+
+func slogHandlerStackTrace(t *testing.T) {
+	t.Parallel()
+
+	var stack []logging.Source
+	driver := &mock.Driver{LogFn: func(ctx context.Context, entry logging.Entry) {
+		stack = slices.Clone(entry.Stack)
+	}}
+	logger := slog.New(log.NewSlogHandler(log.NewLogger(driver, logging.LevelDebug).WithStackTrace(logging.LevelError)))
+	logger.Warn("no stack")
+	require.Nil(t, stack, "entries below the stack level should not get a stack trace")
+	logger.Error("failed")
+	require.NotEmpty(t, stack, "stack trace should be captured")
+	require.Contains(t, stack[0].Function, "slogHandlerStackTrace", "logging call should be the innermost frame")
+}
+
+func TestSlogDriver(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	driver := log.NewSlogDriver(handler)
+	driver.Log(context.Background(), logging.Entry{
+		Time:    time.Now(),
+		Message: "skipped",
+		Level:   logging.LevelDebug,
+	})
+	driver.Log(context.Background(), logging.Entry{
+		Time:             time.Now(),
+		Message:          "user created",
+		Level:            logging.LevelWarn,
+		Attrs:            []logging.Attr{logging.NewAttr("id", 12)},
+		TraceID:          "test-trace",
+		SpanID:           "test-span",
+		TransactionAttrs: []logging.Attr{logging.NewAttr("requestPath", "/users")},
+	})
+	require.Equal(t, "level=WARN msg=\"user created\" id=12 traceID=test-trace spanID=test-span transaction.requestPath=/users\n",
+		buf.String(), "entry should be handled by the slog handler")
+}
+
+func TestSlogDriver_SourceAndStack(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	driver := log.NewSlogDriver(slog.NewJSONHandler(&buf, nil))
+	source := logging.Source{Function: "main.update", File: "/app/user.go", Line: 12}
+	driver.Log(context.Background(), logging.Entry{
+		Time:    time.Now(),
+		Message: "update failed",
+		Level:   logging.LevelError,
+		Logger:  "db.mysql",
+		Source:  &source,
+		Stack:   []logging.Source{source, {Function: "main.main", File: "/app/main.go", Line: 5}},
+	})
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "db.mysql", record["logger"])
+	expectedSource := map[string]any{"function": "main.update", "file": "/app/user.go", "line": float64(12)}
+	require.Equal(t, expectedSource, record[slog.SourceKey])
+	require.Equal(t, []any{expectedSource, map[string]any{"function": "main.main", "file": "/app/main.go", "line": float64(5)}},
+		record["stack"])
+}
@@ -0,0 +1,214 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/redact"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+type WatchOptions struct {
+	// Interval between two checks of the file modification time. Defaults to 5 seconds, a negative value
+	// disables polling.
+	Interval time.Duration
+	// Signals triggering a reload. Defaults to SIGHUP.
+	Signals []os.Signal
+}
+
+// ConfigWatcher reloads the log section of a config file and replaces the default logger when it changes.
+type ConfigWatcher struct {
+	path string
+
+	mu      sync.Mutex
+	current config.LogConfig
+	modTime time.Time
+	size    int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// WatchConfig starts watching the config file at path, either by polling or on signal.
+// The file is expected to hold the configuration the default logger was created from.
+func WatchConfig(path string, opts WatchOptions) (*ConfigWatcher, error) {
+	var cfg config.Config
+	if err := config.LoadFromYAML(path, &cfg); err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	w := &ConfigWatcher{
+		path:    path,
+		current: cfg.Log,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	w.modTime, w.size = w.stat()
+	if opts.Interval == 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if len(opts.Signals) == 0 {
+		opts.Signals = []os.Signal{syscall.SIGHUP}
+	}
+	go w.run(opts)
+	return w, nil
+}
+
+func (w *ConfigWatcher) run(opts WatchOptions) {
+	defer close(w.done)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, opts.Signals...)
+	defer signal.Stop(sig)
+	var tick <-chan time.Time
+	if opts.Interval > 0 {
+		ticker := time.NewTicker(opts.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-sig:
+			_ = w.Reload()
+		case <-tick:
+			if w.changed() {
+				_ = w.Reload()
+			}
+		}
+	}
+}
+
+// Stop stops watching the file.
+func (w *ConfigWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+// Reload re-reads the config file and, if valid, replaces the default logger with one created from it. Loggers
+// derived from the previous default keep logging with the previous settings, so the previous default is only closed
+// by Shutdown. An invalid config is reported and the current logger is kept.
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.modTime, w.size = w.stat()
+
+	var cfg config.Config
+	err := config.LoadFromYAML(w.path, &cfg)
+	if err == nil {
+		err = validateLogConfig(cfg.Log)
+	}
+	if err != nil {
+		Default().Error(context.Background(), "reload log config: keeping current config", "err", err, "path", w.path)
+		return err
+	}
+	changes := diffConfig(w.current, cfg.Log)
+	if len(changes) == 0 {
+		return nil
+	}
+	// the replaced logger may still be used through its derived loggers or by in-flight calls
+	addShutdownHook(Default().Close)
+	logger := NewLoggerFromConfig(cfg.Log)
+	for prefix := range w.current.Levels {
+		if _, ok := cfg.Log.Levels[prefix]; !ok {
+			// named loggers fall back to the default level
+			unregisterLevel(prefix, logger.Level())
+		}
+	}
+	logger.SetDefault()
+	w.current = cfg.Log
+	Default().Info(context.Background(), "log config reloaded", "path", w.path, "changes", changes)
+	return nil
+}
+
+func (w *ConfigWatcher) changed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	modTime, size := w.stat()
+	return !modTime.Equal(w.modTime) || size != w.size
+}
+
+func (w *ConfigWatcher) stat() (time.Time, int64) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// validateLogConfig checks the levels and redaction patterns NewLoggerFromConfig would silently ignore.
+func validateLogConfig(cfg config.LogConfig) error {
+	var errs []error
+	if cfg.Level != "" {
+		if _, err := logging.ParseLevel(cfg.Level); err != nil {
+			errs = append(errs, fmt.Errorf("level: %w", err))
+		}
+	}
+	if cfg.StackTraceLevel != "" {
+		if _, err := logging.ParseLevel(cfg.StackTraceLevel); err != nil {
+			errs = append(errs, fmt.Errorf("stack_trace_level: %w", err))
+		}
+	}
+	if cfg.UnsampledLevel != "" {
+		if _, err := logging.ParseLevel(cfg.UnsampledLevel); err != nil {
+			errs = append(errs, fmt.Errorf("unsampled_level: %w", err))
+		}
+	}
+	for prefix, level := range cfg.Levels {
+		if _, err := logging.ParseLevel(level); err != nil {
+			errs = append(errs, fmt.Errorf("levels.%s: %w", prefix, err))
+		}
+	}
+	for i, out := range cfg.Outputs {
+		if out.Level == "" {
+			continue
+		}
+		if _, err := logging.ParseLevel(out.Level); err != nil {
+			errs = append(errs, fmt.Errorf("outputs[%d].level: %w", i, err))
+		}
+	}
+	if cfg.Redaction != nil {
+		if _, err := redact.CompilePatterns(cfg.Redaction.Patterns); err != nil {
+			errs = append(errs, fmt.Errorf("redaction.patterns: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// diffConfig describes the changed settings, one `key: old -> new` line per setting, using the yaml keys.
+func diffConfig(old, updated config.LogConfig) []string {
+	var changes []string
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(updated)
+	for i := 0; i < oldValue.NumField(); i++ {
+		before, after := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		key := oldValue.Type().Field(i).Tag.Get("yaml")
+		changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, formatSetting(before), formatSetting(after)))
+	}
+	return changes
+}
+
+func formatSetting(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+		v = rv.Elem().Interface()
+	}
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,107 @@
+package log_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// TestWatchConfig replaces the default logger, so it must not run in parallel with other tests.
+func TestWatchConfig(t *testing.T) {
+	original := log.Default()
+	t.Cleanup(original.SetDefault)
+
+	dir := t.TempDir()
+	configPath, outputPath := filepath.Join(dir, "log-config.yml"), filepath.Join(dir, "app.log")
+	writeConfig := func(level string) {
+		content := "log:\n  level: " + level + "\n  output_file: " + outputPath + "\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	}
+	writeConfig("info")
+
+	w, err := log.WatchConfig(configPath, log.WatchOptions{Interval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	t.Run("reload on change", func(t *testing.T) {
+		writeConfig("debug")
+		require.Eventually(t, func() bool {
+			log.Debug(context.Background(), "debug enabled")
+			out, _ := os.ReadFile(outputPath)
+			return len(out) > 0
+		}, time.Second, 20*time.Millisecond, "new config should be applied")
+		out, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Contains(t, string(out), "log config reloaded", "reload should be logged")
+		require.Contains(t, string(out), "level: info -> debug", "changes should be logged")
+	})
+
+	t.Run("keep current config when invalid", func(t *testing.T) {
+		current := log.Default()
+		writeConfig("verbose")
+		require.Error(t, w.Reload(), "invalid level should be rejected")
+		require.Same(t, current, log.Default(), "current logger should be kept")
+	})
+
+	t.Run("keep current config when redaction pattern invalid", func(t *testing.T) {
+		current := log.Default()
+		content := "log:\n  level: debug\n  redaction:\n    patterns: ['(']\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		require.Error(t, w.Reload(), "invalid pattern should be rejected")
+		require.Same(t, current, log.Default(), "current logger should be kept")
+	})
+
+	t.Run("remove named logger levels", func(t *testing.T) {
+		content := "log:\n  level: info\n  output_file: " + outputPath + "\n  levels:\n    watch-test-db: error\n"
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+		require.NoError(t, w.Reload())
+		named := log.Named("watch-test-db")
+		require.Equal(t, logging.LevelError, named.Level())
+
+		writeConfig("info")
+		require.NoError(t, w.Reload())
+		require.Equal(t, logging.LevelInfo, log.Named("watch-test-db").Level(), "removed level should not be applied")
+		require.Equal(t, logging.LevelInfo, named.Level(), "existing loggers should fall back to the default level")
+	})
+
+	// closes the default logger, so it must run last
+	t.Run("keep previous loggers until shutdown", func(t *testing.T) {
+		named := log.Named("db.mysql")
+		for i, level := range []string{"info", "debug", "warn"} {
+			writeConfig(level)
+			require.NoError(t, w.Reload(), "reload %d should succeed", i)
+		}
+		named.Warn(context.Background(), "logged after reload")
+		out, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.Contains(t, string(out), "logged after reload", "loggers derived before a reload should keep logging")
+
+		if _, err := os.Stat("/proc/self/fd"); err != nil {
+			t.Skip("open files cannot be listed")
+		}
+		require.NoError(t, log.Shutdown(context.Background()))
+		require.Zero(t, openFiles(t, outputPath), "shutdown should close the replaced loggers")
+	})
+}
+
+// openFiles counts the file descriptors of the process referring to path.
+func openFiles(t *testing.T, path string) int {
+	t.Helper()
+
+	fds, err := os.ReadDir("/proc/self/fd")
+	require.NoError(t, err)
+	n := 0
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			n++
+		}
+	}
+	return n
+}
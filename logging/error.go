@@ -0,0 +1,103 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorFielder is implemented by errors exposing structured details, rendered along with their message.
+type ErrorFielder interface {
+	ErrorFields() []Attr
+}
+
+// ErrorDetails is the structured representation of an error attribute value, used by the drivers.
+type ErrorDetails struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	// Fields holds the details of errors implementing ErrorFielder.
+	Fields []Attr `json:"fields,omitempty"`
+	// Causes holds the errors directly wrapped by this one: one for errors.Unwrap, several for errors.Join.
+	Causes []ErrorDetails `json:"causes,omitempty"`
+}
+
+// NewErrorDetails captures the message, type and fields of err and of the errors it wraps.
+func NewErrorDetails(err error) ErrorDetails {
+	details := ErrorDetails{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+	if fielder, ok := err.(ErrorFielder); ok {
+		details.Fields = fielder.ErrorFields()
+	}
+	var wrapped []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		wrapped = e.Unwrap()
+	default:
+		if next := errors.Unwrap(err); next != nil {
+			wrapped = []error{next}
+		}
+	}
+	for _, w := range wrapped {
+		if w != nil {
+			details.Causes = append(details.Causes, NewErrorDetails(w))
+		}
+	}
+	return details
+}
+
+// String renders the details on a single line, like: `msg (type) {key=value} caused by [msg (type)]`.
+func (d ErrorDetails) String() string {
+	var sb strings.Builder
+	d.write(&sb)
+	return sb.String()
+}
+
+func (d ErrorDetails) write(sb *strings.Builder) {
+	_, _ = fmt.Fprintf(sb, "%s (%s)", d.Message, d.Type)
+	if len(d.Fields) > 0 {
+		sb.WriteString(" {")
+		for i, field := range d.Fields {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			_, _ = fmt.Fprintf(sb, "%s=%v", field.Key, field.Value)
+		}
+		sb.WriteByte('}')
+	}
+	if len(d.Causes) > 0 {
+		sb.WriteString(" caused by [")
+		for i, cause := range d.Causes {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			cause.write(sb)
+		}
+		sb.WriteByte(']')
+	}
+}
+
+// ResolveErrors returns attrs with the error values replaced by their ErrorDetails.
+// The given slice is not modified; it is returned as is if it holds no error.
+func ResolveErrors(attrs []Attr) []Attr {
+	var resolved []Attr
+	for i, attr := range attrs {
+		if attr.Value.Kind() != KindAny {
+			continue
+		}
+		err, ok := attr.Value.Any().(error)
+		if !ok {
+			continue
+		}
+		if resolved == nil {
+			resolved = make([]Attr, len(attrs))
+			copy(resolved, attrs)
+		}
+		resolved[i].Value = AnyValue(NewErrorDetails(err))
+	}
+	if resolved == nil {
+		return attrs
+	}
+	return resolved
+}
@@ -0,0 +1,59 @@
+package logging_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string { return "invalid " + e.field }
+
+func (e *validationError) ErrorFields() []logging.Attr {
+	return []logging.Attr{logging.NewAttr("field", e.field)}
+}
+
+func TestNewErrorDetails(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err      error
+		expected string
+	}{
+		"plain": {
+			err:      errors.New("bind failed"),
+			expected: "bind failed (*errors.errorString)",
+		},
+		"wrapped": {
+			err:      fmt.Errorf("update user: %w", errors.New("bind failed")),
+			expected: "update user: bind failed (*fmt.wrapError) caused by [bind failed (*errors.errorString)]",
+		},
+		"joined": {
+			err: errors.Join(errors.New("a"), fmt.Errorf("b: %w", &validationError{field: "email"})),
+			expected: "a\nb: invalid email (*errors.joinError) caused by [a (*errors.errorString), " +
+				"b: invalid email (*fmt.wrapError) caused by [invalid email (*logging_test.validationError) {field=email}]]",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, logging.NewErrorDetails(tc.err).String())
+		})
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	t.Parallel()
+
+	attrs := []logging.Attr{logging.NewAttr("id", 12), logging.NewAttr("err", errors.New("bind failed"))}
+	resolved := logging.ResolveErrors(attrs)
+	require.Equal(t, logging.NewAttr("id", 12), resolved[0])
+	require.Equal(t, logging.ErrorDetails{Message: "bind failed", Type: "*errors.errorString"}, resolved[1].Value.Any())
+	require.IsType(t, errors.New(""), attrs[1].Value.Any(), "given attrs should not be modified")
+}
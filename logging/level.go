@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // A Level represents the severity of a log event.
@@ -50,3 +51,26 @@ func ParseLevel(l string) (Level, error) {
 	}
 	return Level(severity), nil
 }
+
+// LevelVar is a Level variable, safe for concurrent use. Loggers sharing a LevelVar observe its changes.
+type LevelVar struct {
+	val atomic.Int64
+}
+
+func NewLevelVar(level Level) *LevelVar {
+	v := &LevelVar{}
+	v.Set(level)
+	return v
+}
+
+func (v *LevelVar) Level() Level {
+	return Level(v.val.Load())
+}
+
+func (v *LevelVar) Set(level Level) {
+	v.val.Store(int64(level))
+}
+
+func (v *LevelVar) String() string {
+	return fmt.Sprintf("LevelVar(%s)", v.Level())
+}
@@ -81,3 +81,12 @@ func TestParseLevel(t *testing.T) {
 		require.Equal(t, tc.want, lvl, "level should be set correctly")
 	}
 }
+
+func TestLevelVar(t *testing.T) {
+	t.Parallel()
+	v := logging.NewLevelVar(logging.LevelInfo)
+	require.Equal(t, logging.LevelInfo, v.Level())
+	v.Set(logging.LevelDebug)
+	require.Equal(t, logging.LevelDebug, v.Level())
+	require.Equal(t, "LevelVar(DEBUG)", v.String())
+}
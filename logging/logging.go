@@ -2,23 +2,101 @@
 package logging
 
 import (
+	"runtime"
+	"slices"
 	"time"
 )
 
 type Attr struct {
 	Key   string
-	Value any
+	Value Value
 }
 
+// NewAttr returns an attribute holding value with the matching kind. The typed constructors, like String or Int,
+// avoid the conversion to any.
 func NewAttr(name string, value any) Attr {
-	return Attr{Key: name, Value: value}
+	return Attr{Key: name, Value: AnyValue(value)}
+}
+
+// Any is an alias of NewAttr.
+func Any(key string, value any) Attr {
+	return NewAttr(key, value)
+}
+
+func String(key, value string) Attr {
+	return Attr{Key: key, Value: StringValue(value)}
+}
+
+func Int(key string, value int) Attr {
+	return Attr{Key: key, Value: IntValue(value)}
+}
+
+func Int64(key string, value int64) Attr {
+	return Attr{Key: key, Value: Int64Value(value)}
+}
+
+func Uint64(key string, value uint64) Attr {
+	return Attr{Key: key, Value: Uint64Value(value)}
+}
+
+func Float64(key string, value float64) Attr {
+	return Attr{Key: key, Value: Float64Value(value)}
+}
+
+func Bool(key string, value bool) Attr {
+	return Attr{Key: key, Value: BoolValue(value)}
+}
+
+func Time(key string, value time.Time) Attr {
+	return Attr{Key: key, Value: TimeValue(value)}
+}
+
+func Duration(key string, value time.Duration) Attr {
+	return Attr{Key: key, Value: DurationValue(value)}
+}
+
+// Group returns an attribute holding a list of attributes under key.
+func Group(key string, attrs ...Attr) Attr {
+	return Attr{Key: key, Value: GroupValue(attrs...)}
 }
 
 type Entry struct {
-	Time             time.Time
-	Message          string
-	Level            Level
+	Time    time.Time
+	Message string
+	Level   Level
+	// Logger is the name of the logger that created the entry, empty for unnamed loggers.
+	Logger string `json:",omitempty"`
+	// Source is the location of the call that created the entry, set only when enabled on the logger.
+	Source *Source `json:",omitempty"`
+	// Stack is the stack trace of the entry, innermost call first, set only for the levels enabled on the logger.
+	Stack            []Source `json:",omitempty"`
 	Attrs            []Attr
 	TraceID          string
+	SpanID           string
 	TransactionAttrs []Attr
 }
+
+// Clone returns a copy of the entry whose attribute slices do not share memory with the original ones.
+// Drivers keeping an entry after Log returns must clone it, since the logger may reuse its slices.
+func (e Entry) Clone() Entry {
+	e.Attrs = slices.Clone(e.Attrs)
+	e.TransactionAttrs = slices.Clone(e.TransactionAttrs)
+	return e
+}
+
+// Source describes the location of a line of source code.
+type Source struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// NewSource resolves the program counter of a call site, as returned by runtime.Callers.
+func NewSource(pc uintptr) *Source {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return &Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LogValuer is implemented by values that are expensive to compute, like a serialized struct.
+// LogValue is called only when an entry holding the value is emitted.
+type LogValuer interface {
+	LogValue() Value
+}
+
+const (
+	// maxLogValues bounds the LogValue calls resolving a single value, stopping LogValuers returning themselves.
+	maxLogValues = 100
+	// maxGroupDepth bounds the nesting of the resolved groups, stopping LogValuers returning groups holding themselves.
+	maxGroupDepth = 32
+)
+
+// Resolve calls LogValue on v while it holds a LogValuer and returns the result. A LogValue call panicking or
+// exceeding the number of calls allowed results in a value holding an error. The members of groups are not resolved.
+func (v Value) Resolve() (rv Value) {
+	orig := v
+	defer func() {
+		if r := recover(); r != nil {
+			rv = AnyValue(fmt.Errorf("LogValue panicked: %v", r))
+		}
+	}()
+	for i := 0; i < maxLogValues; i++ {
+		if v.Kind() != KindAny {
+			return v
+		}
+		lv, ok := v.any.(LogValuer)
+		if !ok {
+			return v
+		}
+		v = lv.LogValue()
+	}
+	return AnyValue(fmt.Errorf("LogValue called too many times on Value of type %T", orig.Any()))
+}
+
+var errGroupTooDeep = errors.New("LogValue resolved to groups nested too deeply")
+
+// ResolveAttrs returns attrs with the LogValuer values resolved, including the ones of group members.
+// The given slice and groups are not modified; they are returned as is if they hold no LogValuer.
+func ResolveAttrs(attrs []Attr) []Attr {
+	return resolveAttrs(attrs, 0)
+}
+
+func resolveAttrs(attrs []Attr, depth int) []Attr {
+	var resolved []Attr
+	for i, attr := range attrs {
+		v := attr.Value
+		switch v.Kind() {
+		case KindAny:
+			if _, ok := v.any.(LogValuer); !ok {
+				continue
+			}
+			v = v.Resolve()
+			if v.Kind() == KindGroup {
+				v = resolveGroup(v, depth+1)
+			}
+		case KindGroup:
+			group := resolveGroup(v, depth+1)
+			if group.any == v.any {
+				continue
+			}
+			v = group
+		default:
+			continue
+		}
+		if resolved == nil {
+			resolved = make([]Attr, len(attrs))
+			copy(resolved, attrs)
+		}
+		resolved[i].Value = v
+	}
+	if resolved == nil {
+		return attrs
+	}
+	return resolved
+}
+
+func resolveGroup(v Value, depth int) Value {
+	if depth > maxGroupDepth {
+		return AnyValue(errGroupTooDeep)
+	}
+	members := v.Group()
+	if len(members) == 0 {
+		return v
+	}
+	resolved := resolveAttrs(members, depth)
+	if &resolved[0] == &members[0] {
+		return v
+	}
+	return GroupValue(resolved...)
+}
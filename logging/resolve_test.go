@@ -0,0 +1,91 @@
+package logging_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+type user struct {
+	id    int
+	calls *int
+}
+
+func (u user) LogValue() logging.Value {
+	*u.calls++
+	return logging.GroupValue(logging.Int("id", u.id))
+}
+
+type selfValuer struct{}
+
+func (selfValuer) LogValue() logging.Value {
+	return logging.AnyValue(selfValuer{})
+}
+
+type nestingValuer struct{}
+
+func (nestingValuer) LogValue() logging.Value {
+	return logging.GroupValue(logging.Any("inner", nestingValuer{}))
+}
+
+type panickingValuer struct{}
+
+func (panickingValuer) LogValue() logging.Value {
+	panic("boom")
+}
+
+func TestValue_Resolve(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	resolved := logging.AnyValue(user{id: 12, calls: &calls}).Resolve()
+	require.Equal(t, logging.KindGroup, resolved.Kind())
+	require.Equal(t, 1, calls)
+	require.Equal(t, logging.IntValue(5), logging.IntValue(5).Resolve(), "other values should be kept")
+
+	tests := map[string]struct {
+		value    logging.Value
+		expected string
+	}{
+		"self": {
+			value:    logging.AnyValue(selfValuer{}),
+			expected: "LogValue called too many times on Value of type logging_test.selfValuer",
+		},
+		"panic": {
+			value:    logging.AnyValue(panickingValuer{}),
+			expected: "LogValue panicked: boom",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err, ok := tc.value.Resolve().Any().(error)
+			require.True(t, ok, "an error should be returned")
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestResolveAttrs(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	attrs := []logging.Attr{
+		logging.String("env", "test"),
+		logging.Group("request", logging.Any("user", user{id: 12, calls: &calls})),
+	}
+	expected := []logging.Attr{
+		logging.String("env", "test"),
+		logging.Group("request", logging.Group("user", logging.Int("id", 12))),
+	}
+	require.True(t, slices.EqualFunc(expected, logging.ResolveAttrs(attrs), logging.Attr.Equal), "group members should be resolved")
+	require.Equal(t, logging.KindAny, attrs[1].Value.Group()[0].Value.Kind(), "given attrs should not be modified")
+
+	plain := []logging.Attr{logging.String("env", "test")}
+	require.Equal(t, &plain[0], &logging.ResolveAttrs(plain)[0], "attrs without LogValuer should be returned as is")
+
+	deep := logging.ResolveAttrs([]logging.Attr{logging.Any("deep", nestingValuer{})})
+	require.Contains(t, deep[0].String(), "nested too deeply", "recursive groups should be stopped")
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+)
+
+// NewStack resolves the program counters returned by runtime.Callers into a stack trace, innermost call first.
+func NewStack(pcs []uintptr) []Source {
+	stack := make([]Source, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			stack = append(stack, Source{Function: frame.Function, File: frame.File, Line: frame.Line})
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// StackFromError returns the stack trace carried by err or by the errors it wraps, preferring the innermost one,
+// closest to where the error originated. An error carries a stack trace if it has a StackTrace method returning
+// a slice of program counters, like the errors created by github.com/pkg/errors.
+func StackFromError(err error) []Source {
+	var stack []Source
+	for err != nil {
+		if pcs, ok := stackTracePCs(err); ok {
+			stack = NewStack(pcs)
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				if s := StackFromError(e); s != nil {
+					return s
+				}
+			}
+			break
+		}
+		err = errors.Unwrap(err)
+	}
+	return stack
+}
+
+// stackTracePCs calls the StackTrace method of err through reflection, since the returned frame type differs
+// between libraries.
+func stackTracePCs(err error) ([]uintptr, bool) {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return nil, false
+	}
+	out := method.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+	frames := method.Call(nil)[0]
+	pcs := make([]uintptr, frames.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(frames.Index(i).Uint())
+	}
+	return pcs, true
+}
@@ -0,0 +1,71 @@
+package logging_test
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// frame mimics the frame type of github.com/pkg/errors.
+type frame uintptr
+
+type stackError struct {
+	msg   string
+	stack []frame
+}
+
+func newStackError(msg string) error {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:])
+	stack := make([]frame, n)
+	for i, pc := range pcs[:n] {
+		stack[i] = frame(pc)
+	}
+	return &stackError{msg: msg, stack: stack}
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() []frame { return e.stack }
+
+func TestStackFromError(t *testing.T) {
+	t.Parallel()
+
+	origin := newStackError("origin")
+	tests := map[string]struct {
+		err       error
+		withStack bool
+	}{
+		"no stack": {
+			err: errors.New("plain"),
+		},
+		"direct": {
+			err:       origin,
+			withStack: true,
+		},
+		"wrapped": {
+			err:       fmt.Errorf("update user: %w", origin),
+			withStack: true,
+		},
+		"joined": {
+			err:       errors.Join(errors.New("plain"), fmt.Errorf("bind: %w", origin)),
+			withStack: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			stack := logging.StackFromError(tc.err)
+			if !tc.withStack {
+				require.Nil(t, stack)
+				return
+			}
+			require.NotEmpty(t, stack, "stack trace should be extracted")
+			require.Contains(t, stack[0].Function, "TestStackFromError", "innermost frame should come first")
+		})
+	}
+}
@@ -0,0 +1,385 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+	"unsafe"
+)
+
+// Kind is the type of the data held by a Value.
+type Kind int
+
+const (
+	KindAny Kind = iota
+	KindBool
+	KindDuration
+	KindFloat64
+	KindInt64
+	KindString
+	KindTime
+	KindUint64
+	KindGroup
+)
+
+var kindNames = []string{"Any", "Bool", "Duration", "Float64", "Int64", "String", "Time", "Uint64", "Group"}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "<unknown logging.Kind>"
+}
+
+// Value holds any Go value without allocating for the common kinds. The zero Value holds nil.
+type Value struct {
+	// num holds the numeric kinds, the nanoseconds of a time, or the length of a string or group.
+	num uint64
+	// any holds the Kind for the numeric kinds, a pointer to the data of a string or group, the location of a time
+	// or the value itself for KindAny. Storing pointers and small integers in an interface does not allocate.
+	any any
+}
+
+type (
+	stringptr    *byte
+	groupptr     *Attr
+	timeLocation *time.Location
+	// timeTime holds the times that cannot be represented as Unix nanoseconds.
+	timeTime time.Time
+)
+
+func StringValue(value string) Value {
+	return Value{num: uint64(len(value)), any: stringptr(unsafe.StringData(value))}
+}
+
+func IntValue(value int) Value {
+	return Int64Value(int64(value))
+}
+
+func Int64Value(value int64) Value {
+	return Value{num: uint64(value), any: KindInt64}
+}
+
+func Uint64Value(value uint64) Value {
+	return Value{num: value, any: KindUint64}
+}
+
+func Float64Value(value float64) Value {
+	return Value{num: math.Float64bits(value), any: KindFloat64}
+}
+
+func BoolValue(value bool) Value {
+	var num uint64
+	if value {
+		num = 1
+	}
+	return Value{num: num, any: KindBool}
+}
+
+func DurationValue(value time.Duration) Value {
+	return Value{num: uint64(value.Nanoseconds()), any: KindDuration}
+}
+
+// TimeValue drops the monotonic clock reading of value.
+func TimeValue(value time.Time) Value {
+	if value.IsZero() {
+		return Value{any: timeLocation(nil)}
+	}
+	nsec := value.UnixNano()
+	if !time.Unix(0, nsec).Equal(value) {
+		return Value{any: timeTime(value.Round(0))}
+	}
+	return Value{num: uint64(nsec), any: timeLocation(value.Location())}
+}
+
+// GroupValue holds a list of attributes. The given slice must not be modified afterward.
+func GroupValue(attrs ...Attr) Value {
+	return Value{num: uint64(len(attrs)), any: groupptr(unsafe.SliceData(attrs))}
+}
+
+// AnyValue returns a Value of the kind matching the type of value, falling back to KindAny.
+func AnyValue(value any) Value {
+	switch v := value.(type) {
+	case string:
+		return StringValue(v)
+	case int:
+		return IntValue(v)
+	case int8:
+		return Int64Value(int64(v))
+	case int16:
+		return Int64Value(int64(v))
+	case int32:
+		return Int64Value(int64(v))
+	case int64:
+		return Int64Value(v)
+	case uint:
+		return Uint64Value(uint64(v))
+	case uint8:
+		return Uint64Value(uint64(v))
+	case uint16:
+		return Uint64Value(uint64(v))
+	case uint32:
+		return Uint64Value(uint64(v))
+	case uint64:
+		return Uint64Value(v)
+	case float32:
+		return Float64Value(float64(v))
+	case float64:
+		return Float64Value(v)
+	case bool:
+		return BoolValue(v)
+	case time.Duration:
+		return DurationValue(v)
+	case time.Time:
+		return TimeValue(v)
+	case []Attr:
+		return GroupValue(v...)
+	case Value:
+		return v
+	case Kind:
+		// a Kind would be mistaken for the marker of a numeric kind
+		return Value{any: kindAny{v}}
+	default:
+		return Value{any: v}
+	}
+}
+
+// kindAny wraps a Kind held as KindAny.
+type kindAny struct {
+	Kind
+}
+
+func (v Value) Kind() Kind {
+	switch x := v.any.(type) {
+	case Kind:
+		return x
+	case stringptr:
+		return KindString
+	case timeLocation, timeTime:
+		return KindTime
+	case groupptr:
+		return KindGroup
+	default:
+		return KindAny
+	}
+}
+
+// Any returns the value as an any, allocating for the kinds not stored as such.
+func (v Value) Any() any {
+	switch v.Kind() {
+	case KindAny:
+		if k, ok := v.any.(kindAny); ok {
+			return k.Kind
+		}
+		return v.any
+	case KindBool:
+		return v.Bool()
+	case KindDuration:
+		return v.Duration()
+	case KindFloat64:
+		return v.Float64()
+	case KindInt64:
+		return v.Int64()
+	case KindString:
+		return v.str()
+	case KindTime:
+		return v.Time()
+	case KindUint64:
+		return v.Uint64()
+	case KindGroup:
+		return v.Group()
+	default:
+		panic(fmt.Sprintf("bad kind: %s", v.Kind()))
+	}
+}
+
+// String returns the string held by a KindString value, or a representation of the value for the other kinds.
+// It does not allocate for KindString.
+func (v Value) String() string {
+	if sp, ok := v.any.(stringptr); ok {
+		return unsafe.String(sp, v.num)
+	}
+	return string(v.AppendText(nil))
+}
+
+func (v Value) str() string {
+	return unsafe.String(v.any.(stringptr), v.num)
+}
+
+// Int64 returns the value of a KindInt64 value and panics for the other kinds.
+func (v Value) Int64() int64 {
+	if g, w := v.Kind(), KindInt64; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return int64(v.num)
+}
+
+// Uint64 returns the value of a KindUint64 value and panics for the other kinds.
+func (v Value) Uint64() uint64 {
+	if g, w := v.Kind(), KindUint64; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return v.num
+}
+
+// Float64 returns the value of a KindFloat64 value and panics for the other kinds.
+func (v Value) Float64() float64 {
+	if g, w := v.Kind(), KindFloat64; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return math.Float64frombits(v.num)
+}
+
+// Bool returns the value of a KindBool value and panics for the other kinds.
+func (v Value) Bool() bool {
+	if g, w := v.Kind(), KindBool; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return v.num == 1
+}
+
+// Duration returns the value of a KindDuration value and panics for the other kinds.
+func (v Value) Duration() time.Duration {
+	if g, w := v.Kind(), KindDuration; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return time.Duration(int64(v.num))
+}
+
+// Time returns the value of a KindTime value and panics for the other kinds.
+func (v Value) Time() time.Time {
+	switch a := v.any.(type) {
+	case timeLocation:
+		if a == nil {
+			return time.Time{}
+		}
+		return time.Unix(0, int64(v.num)).In(a)
+	case timeTime:
+		return time.Time(a)
+	default:
+		panic(fmt.Sprintf("Value kind is %s, not %s", v.Kind(), KindTime))
+	}
+}
+
+// Group returns the attributes of a KindGroup value and panics for the other kinds.
+func (v Value) Group() []Attr {
+	if sp, ok := v.any.(groupptr); ok {
+		return unsafe.Slice((*Attr)(sp), v.num)
+	}
+	panic(fmt.Sprintf("Value kind is %s, not %s", v.Kind(), KindGroup))
+}
+
+// Equal reports whether v and w hold the same Go value.
+func (v Value) Equal(w Value) bool {
+	k1, k2 := v.Kind(), w.Kind()
+	if k1 != k2 {
+		return false
+	}
+	switch k1 {
+	case KindInt64, KindUint64, KindBool, KindDuration:
+		return v.num == w.num
+	case KindString:
+		return v.str() == w.str()
+	case KindFloat64:
+		return v.Float64() == w.Float64()
+	case KindTime:
+		return v.Time().Equal(w.Time())
+	case KindAny:
+		return reflect.DeepEqual(v.any, w.any)
+	case KindGroup:
+		return slices.EqualFunc(v.Group(), w.Group(), Attr.Equal)
+	default:
+		panic(fmt.Sprintf("bad kind: %s", k1))
+	}
+}
+
+// AppendText appends a text representation of v to dst, like fmt %v, without allocating for the kinds other than
+// KindAny, KindDuration and KindGroup.
+func (v Value) AppendText(dst []byte) []byte {
+	switch v.Kind() {
+	case KindString:
+		return append(dst, v.str()...)
+	case KindInt64:
+		return strconv.AppendInt(dst, int64(v.num), 10)
+	case KindUint64:
+		return strconv.AppendUint(dst, v.num, 10)
+	case KindFloat64:
+		return strconv.AppendFloat(dst, v.Float64(), 'g', -1, 64)
+	case KindBool:
+		return strconv.AppendBool(dst, v.Bool())
+	case KindDuration:
+		return append(dst, v.Duration().String()...)
+	case KindTime:
+		// the layout of time.Time.String, without the monotonic clock reading dropped by TimeValue
+		return v.Time().AppendFormat(dst, "2006-01-02 15:04:05.999999999 -0700 MST")
+	default:
+		return fmt.Append(dst, v.Any())
+	}
+}
+
+// MarshalJSON encodes the Go value held by v; groups are encoded as objects.
+func (v Value) MarshalJSON() ([]byte, error) {
+	if v.Kind() != KindGroup {
+		return json.Marshal(v.Any())
+	}
+	buf := []byte{'{'}
+	for i, attr := range v.Group() {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(attr.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := attr.Value.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(append(buf, key...), ':'), value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON decodes any JSON value, holding it with the matching kind.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*v = AnyValue(value)
+	return nil
+}
+
+// Equal reports whether a and b have the same key and equal values.
+func (a Attr) Equal(b Attr) bool {
+	return a.Key == b.Key && a.Value.Equal(b.Value)
+}
+
+func (a Attr) String() string {
+	return a.Key + "=" + a.Value.String()
+}
+
+// Flatten replaces the group attributes by their members, whose keys are prefixed with the group key and a dot,
+// for outputs without nested attributes. The given slice is returned as is if it holds no group.
+func Flatten(attrs []Attr) []Attr {
+	if !slices.ContainsFunc(attrs, func(attr Attr) bool { return attr.Value.Kind() == KindGroup }) {
+		return attrs
+	}
+	return appendFlattened(make([]Attr, 0, len(attrs)), "", attrs)
+}
+
+func appendFlattened(dst []Attr, prefix string, attrs []Attr) []Attr {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == KindGroup {
+			dst = appendFlattened(dst, prefix+attr.Key+".", attr.Value.Group())
+			continue
+		}
+		attr.Key = prefix + attr.Key
+		dst = append(dst, attr)
+	}
+	return dst
+}
@@ -0,0 +1,87 @@
+package logging_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+func TestAnyValue(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	err := errors.New("bind failed")
+	tests := map[string]struct {
+		value    any
+		kind     logging.Kind
+		expected any
+		text     string
+	}{
+		"string":   {value: "test", kind: logging.KindString, expected: "test", text: "test"},
+		"int":      {value: 12, kind: logging.KindInt64, expected: int64(12), text: "12"},
+		"int8":     {value: int8(-3), kind: logging.KindInt64, expected: int64(-3), text: "-3"},
+		"uint":     {value: uint(7), kind: logging.KindUint64, expected: uint64(7), text: "7"},
+		"float":    {value: 0.25, kind: logging.KindFloat64, expected: 0.25, text: "0.25"},
+		"bool":     {value: true, kind: logging.KindBool, expected: true, text: "true"},
+		"duration": {value: 1500 * time.Millisecond, kind: logging.KindDuration, expected: 1500 * time.Millisecond, text: "1.5s"},
+		"time":     {value: now, kind: logging.KindTime, expected: now.Round(0), text: now.Round(0).String()},
+		"group": {
+			value:    []logging.Attr{logging.Int("id", 1)},
+			kind:     logging.KindGroup,
+			expected: []logging.Attr{logging.Int("id", 1)},
+			text:     "[id=1]",
+		},
+		"kind":  {value: logging.KindString, kind: logging.KindAny, expected: logging.KindString, text: "String"},
+		"error": {value: err, kind: logging.KindAny, expected: err, text: "bind failed"},
+		"nil":   {value: nil, kind: logging.KindAny, expected: nil, text: "<nil>"},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := logging.AnyValue(tc.value)
+			require.Equal(t, tc.kind, v.Kind())
+			require.Equal(t, tc.expected, v.Any())
+			require.Equal(t, tc.text, v.String())
+			require.True(t, v.Equal(logging.AnyValue(tc.value)), "value should equal itself")
+		})
+	}
+}
+
+func TestValue_Equal(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, logging.StringValue("abc").Equal(logging.StringValue("abc")))
+	require.False(t, logging.StringValue("abc").Equal(logging.StringValue("axy")), "strings of the same length should be compared")
+	require.False(t, logging.IntValue(1).Equal(logging.Uint64Value(1)), "kinds should be compared")
+	require.True(t, logging.GroupValue(logging.Int("a", 1)).Equal(logging.GroupValue(logging.Int("a", 1))))
+	require.False(t, logging.GroupValue(logging.Int("a", 1)).Equal(logging.GroupValue(logging.Int("a", 2))))
+}
+
+func TestValue_Time(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, logging.TimeValue(time.Time{}).Time().IsZero(), "zero time should be kept")
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, far, logging.TimeValue(far).Time(), "times out of the Unix nanoseconds range should be kept")
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	attrs := []logging.Attr{
+		logging.String("env", "test"),
+		logging.Group("http", logging.String("method", "GET"), logging.Group("response", logging.Int("status", 200))),
+	}
+	expected := []logging.Attr{
+		logging.String("env", "test"),
+		logging.String("http.method", "GET"),
+		logging.Int("http.response.status", 200),
+	}
+	require.True(t, slices.EqualFunc(expected, logging.Flatten(attrs), logging.Attr.Equal))
+	flat := []logging.Attr{logging.String("env", "test")}
+	require.Equal(t, flat, logging.Flatten(flat), "attrs without groups should be returned as is")
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+// Gin returns a gin middleware that runs every request inside a transaction named after its route pattern.
+// The incoming W3C trace context, if any, is continued. A panic of the next handlers is recorded as a 500 response.
+func Gin(opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unknownRoute
+		}
+		ctx := transaction.Extract(c.Request.Context(), c.Request.Header)
+		tx, ctx := o.getTracer().StartTransaction(ctx, c.Request.Method+" "+route, transactionType, requestAttrs(c.Request)...)
+		c.Request = c.Request.WithContext(ctx)
+		completed := false
+		defer func() {
+			status := c.Writer.Status()
+			if !completed {
+				// a handler panicked and no recovery middleware ran after this one
+				status = http.StatusInternalServerError
+			}
+			// gin reports -1 if nothing was written
+			o.endTransaction(c.Request, tx, status, max(c.Writer.Size(), 0))
+		}()
+		c.Next()
+		completed = true
+	}
+}
@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/middleware"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestGin(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Gin(middleware.WithTracer(recordingTracer(&tx, &name))))
+	r.GET("/users/:id", func(c *gin.Context) {
+		require.Equal(t, tx, transaction.FromContext(c.Request.Context()), "request context should carry the transaction")
+		c.String(http.StatusOK, "hello")
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12", nil))
+
+	require.Equal(t, "GET /users/:id", name, "transaction should be named after the route pattern")
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusOK))
+	require.Contains(t, tx.Attrs, logging.NewAttr("responseSize", 5))
+	require.Equal(t, "HTTP 2xx", tx.Result())
+	require.Equal(t, transaction.OutcomeSuccess, tx.Outcome())
+	require.NotEmpty(t, tx.GetDuration(), "transaction should be ended")
+}
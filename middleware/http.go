@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+// Handler wraps next so that every request runs inside a transaction named after its route pattern, resolved with
+// the mux set by WithServeMux. Without it, every transaction is named "METHOD unknown route".
+// The incoming W3C trace context, if any, is continued. A panic of next is recorded as a 500 response.
+func Handler(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := transaction.Extract(r.Context(), r.Header)
+		tx, ctx := o.getTracer().StartTransaction(ctx, o.transactionName(r), transactionType, requestAttrs(r)...)
+		r = r.WithContext(ctx)
+		rw := &responseWriter{ResponseWriter: w}
+		completed := false
+		defer func() {
+			status := rw.statusCode()
+			if !completed {
+				// next panicked, the server aborts the response
+				status = http.StatusInternalServerError
+			}
+			o.endTransaction(r, tx, status, rw.size)
+		}()
+		next.ServeHTTP(rw, r)
+		completed = true
+	})
+}
+
+func (o *options) transactionName(r *http.Request) string {
+	if o.mux == nil {
+		return r.Method + " " + unknownRoute
+	}
+	_, pattern := o.mux.Handler(r)
+	switch {
+	case pattern == "":
+		return r.Method + " " + unknownRoute
+	case strings.HasPrefix(pattern, r.Method+" "):
+		return pattern
+	default:
+		return r.Method + " " + pattern
+	}
+}
+
+// responseWriter records the status code and the size of the response.
+type responseWriter struct {
+	http.ResponseWriter
+
+	status int
+	size   int
+}
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	if rw.status == 0 {
+		rw.status = statusCode
+	}
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += n
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to access the original writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func (rw *responseWriter) statusCode() int {
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
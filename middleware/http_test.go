@@ -0,0 +1,139 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/middleware"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestHandler(t *testing.T) {
+	t.Run("transaction named after route pattern", namedAfterRoutePattern)
+	t.Run("unknown route", unknownRouteTransaction)
+	t.Run("access log", accessLog)
+	t.Run("result and outcome", resultAndOutcome)
+	t.Run("panic recorded as server error", panicRecordedAsServerError)
+}
+
+// recordingTracer returns a tracer that stores the started transaction and its name in the given pointers.
+func recordingTracer(tx **transaction.Transaction, name *string) *transaction.Tracer {
+	return transaction.NewTracer(&mock.TransactionRecorder{
+		RecordTransactionFn: func(ctx context.Context, txName, transactionType string) (*transaction.Transaction, context.Context) {
+			*tx = &transaction.Transaction{TraceID: "test-trace"}
+			*name = txName
+			return *tx, ctx
+		},
+	})
+}
+
+func namedAfterRoutePattern(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, tx, transaction.FromContext(r.Context()), "handler context should carry the transaction")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hello"))
+	})
+	handler := middleware.Handler(mux, middleware.WithTracer(recordingTracer(&tx, &name)), middleware.WithServeMux(mux))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/12", nil))
+
+	require.Equal(t, "GET /users/{id}", name, "transaction should be named after the route pattern")
+	require.Contains(t, tx.Attrs, logging.NewAttr("requestPath", "/users/12"))
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusAccepted))
+	require.Contains(t, tx.Attrs, logging.NewAttr("responseSize", 5))
+	require.NotEmpty(t, tx.GetDuration(), "transaction should be ended")
+}
+
+func unknownRouteTransaction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := middleware.Handler(mux, middleware.WithTracer(recordingTracer(&tx, &name)), middleware.WithServeMux(mux))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders/12", nil))
+
+	require.Equal(t, "POST unknown route", name, "unmatched requests should not be named after the raw path")
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusNotFound))
+}
+
+func accessLog(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	driver := &mock.Driver{
+		LogFn: func(ctx context.Context, entry logging.Entry) {
+			require.Equal(t, "request completed", entry.Message)
+			require.Equal(t, "test-trace", entry.TraceID, "access log should be correlated to the transaction")
+			require.Contains(t, entry.Attrs, logging.NewAttr("statusCode", http.StatusOK))
+		},
+	}
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		middleware.WithTracer(recordingTracer(&tx, &name)),
+		middleware.WithAccessLog(log.NewLogger(driver, logging.LevelInfo)),
+	)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 1, driver.Count, "access log line should be emitted")
+}
+
+func resultAndOutcome(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		status  int
+		result  string
+		outcome transaction.Outcome
+	}{
+		{http.StatusCreated, "HTTP 2xx", transaction.OutcomeSuccess},
+		{http.StatusNotFound, "HTTP 4xx", transaction.OutcomeSuccess},
+		{http.StatusBadGateway, "HTTP 5xx", transaction.OutcomeFailure},
+	} {
+		var (
+			tx   *transaction.Transaction
+			name string
+		)
+		handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		}), middleware.WithTracer(recordingTracer(&tx, &name)))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, tc.result, tx.Result(), "result should be the status class of %d", tc.status)
+		require.Equal(t, tc.outcome, tx.Outcome(), "unexpected outcome for %d", tc.status)
+	}
+}
+
+func panicRecordedAsServerError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	handler := middleware.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), middleware.WithTracer(recordingTracer(&tx, &name)))
+	require.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}, "panic should be left to the server")
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusInternalServerError))
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome())
+	require.NotEmpty(t, tx.GetDuration(), "transaction should be ended")
+}
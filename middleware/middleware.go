@@ -0,0 +1,119 @@
+// Package middleware provides net/http and gin middleware that start and end a transaction for each request.
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+const transactionType = "request"
+
+// unknownRoute names transactions of requests that did not match any route, to avoid naming them after the raw path.
+const unknownRoute = "unknown route"
+
+type options struct {
+	tracer      *transaction.Tracer
+	accessLog   *log.Logger
+	mux         *http.ServeMux
+	panicLogger *log.Logger
+	repanic     bool
+}
+
+type Option func(*options)
+
+// WithTracer sets the tracer used to start transactions. Defaults to transaction.DefaultTracer().
+func WithTracer(tracer *transaction.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithAccessLog enables an info level access-log line, emitted through logger once the request is handled.
+func WithAccessLog(logger *log.Logger) Option {
+	return func(o *options) {
+		o.accessLog = logger
+	}
+}
+
+// WithServeMux sets the mux used to resolve the route pattern of a request for the net/http middleware.
+// Without it, transactions are named after the request method only.
+func WithServeMux(mux *http.ServeMux) Option {
+	return func(o *options) {
+		o.mux = mux
+	}
+}
+
+// WithPanicLogger sets the logger used by the recovering middleware. Defaults to log.Default().
+func WithPanicLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.panicLogger = logger
+	}
+}
+
+// WithRepanic makes the recovering middleware panic again once the panic is logged,
+// leaving the response to an outer recovery mechanism.
+func WithRepanic() Option {
+	return func(o *options) {
+		o.repanic = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *options) getTracer() *transaction.Tracer {
+	if o.tracer != nil {
+		return o.tracer
+	}
+	return transaction.DefaultTracer()
+}
+
+func (o *options) getPanicLogger() *log.Logger {
+	if o.panicLogger != nil {
+		return o.panicLogger
+	}
+	return log.Default()
+}
+
+func requestAttrs(r *http.Request) []logging.Attr {
+	return []logging.Attr{
+		logging.NewAttr("requestPath", r.URL.Path),
+		logging.NewAttr("requestMethod", r.Method),
+		logging.NewAttr("callerIP", r.RemoteAddr),
+		logging.NewAttr("userAgent", r.UserAgent()),
+	}
+}
+
+// endTransaction records the response details on tx, ends it and emits the access-log line, if enabled.
+// The result is the status class, like "HTTP 2xx", and server errors mark the transaction as failed. Other statuses
+// mark it as successful, unless an error recorded during the request already marked it as failed.
+func (o *options) endTransaction(r *http.Request, tx *transaction.Transaction, status, size int) {
+	tx.SetResult(fmt.Sprintf("HTTP %dxx", status/100))
+	if status >= http.StatusInternalServerError {
+		tx.SetOutcome(transaction.OutcomeFailure)
+	} else if tx.Outcome() == transaction.OutcomeUnknown {
+		tx.SetOutcome(transaction.OutcomeSuccess)
+	}
+	tx.AddAttrs(
+		logging.NewAttr("statusCode", status),
+		logging.NewAttr("responseSize", size),
+	)
+	tx.End()
+	if o.accessLog != nil {
+		o.accessLog.Info(r.Context(), "request completed",
+			"statusCode", status,
+			"responseSize", size,
+			"duration", tx.GetDuration().Round(time.Microsecond).String(),
+		)
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recoverer wraps next so that a panic while handling a request is logged with its stack trace, marks the request
+// transaction as failed and results in a 500 response, unless the handler already wrote the response headers.
+// It must be wrapped by Handler to find the transaction.
+// http.ErrAbortHandler is not logged and is always propagated, to let net/http abort the response.
+func Recoverer(next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the writer of Handler is reused when found, so the status recorded for the transaction includes the 500
+		rw, ok := w.(*responseWriter)
+		if !ok {
+			rw = &responseWriter{ResponseWriter: w}
+		}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+			o.getPanicLogger().LogPanic(r.Context(), v)
+			if o.repanic {
+				panic(v)
+			}
+			// the status of a response already started cannot be changed
+			if rw.status == 0 {
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// GinRecovery returns a gin middleware replacing gin.Recovery: a panic while handling a request is logged with its
+// stack trace, marks the request transaction as failed and aborts the request with a 500 response.
+// It must be registered after Gin to find the transaction.
+func GinRecovery(opts ...Option) gin.HandlerFunc {
+	o := newOptions(opts)
+	return func(c *gin.Context) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+			o.getPanicLogger().LogPanic(c.Request.Context(), v)
+			if o.repanic {
+				panic(v)
+			}
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
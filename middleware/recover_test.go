@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/middleware"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestRecoverer(t *testing.T) {
+	t.Run("recover", recoverPanic)
+	t.Run("repanic", repanic)
+	t.Run("abort handler", abortHandler)
+	t.Run("headers already written", headersAlreadyWritten)
+}
+
+// headerCounter counts the WriteHeader calls reaching the underlying writer.
+type headerCounter struct {
+	*httptest.ResponseRecorder
+	calls int
+}
+
+func (w *headerCounter) WriteHeader(statusCode int) {
+	w.calls++
+	w.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func panicLogger(t *testing.T) (*log.Logger, *mock.Driver) {
+	driver := &mock.Driver{
+		LogFn: func(ctx context.Context, entry logging.Entry) {
+			require.Equal(t, "panic recovered", entry.Message)
+			require.Equal(t, "test-trace", entry.TraceID, "panic should be correlated to the transaction")
+			require.NotEmpty(t, entry.Stack, "stack trace should be logged")
+		},
+	}
+	return log.NewLogger(driver, logging.LevelInfo), driver
+}
+
+func recoverPanic(t *testing.T) {
+	t.Parallel()
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	logger, driver := panicLogger(t)
+	handler := middleware.Handler(
+		middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}), middleware.WithPanicLogger(logger)),
+		middleware.WithTracer(recordingTracer(&tx, &name)),
+	)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome(), "transaction should be marked as failed")
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusInternalServerError))
+	require.NotEmpty(t, tx.GetDuration(), "transaction should be ended")
+}
+
+func repanic(t *testing.T) {
+	t.Parallel()
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	logger, driver := panicLogger(t)
+	handler := middleware.Handler(
+		middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}), middleware.WithPanicLogger(logger), middleware.WithRepanic()),
+		middleware.WithTracer(recordingTracer(&tx, &name)),
+	)
+	require.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+	require.NotEmpty(t, tx.GetDuration(), "transaction should be ended")
+}
+
+func abortHandler(t *testing.T) {
+	t.Parallel()
+	driver := &mock.Driver{}
+	handler := middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), middleware.WithPanicLogger(log.NewLogger(driver, logging.LevelInfo)))
+	require.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	require.Zero(t, driver.Count, "aborted requests should not be logged")
+}
+
+func headersAlreadyWritten(t *testing.T) {
+	t.Parallel()
+	logger, driver := panicLogger(t)
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	handler := middleware.Handler(
+		middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("partial"))
+			panic("boom")
+		}), middleware.WithPanicLogger(logger)),
+		middleware.WithTracer(recordingTracer(&tx, &name)),
+	)
+	rec := &headerCounter{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, 1, rec.calls, "status should not be written twice")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+}
+
+func TestGinRecovery(t *testing.T) {
+	t.Parallel()
+	var (
+		tx   *transaction.Transaction
+		name string
+	)
+	logger, driver := panicLogger(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Gin(middleware.WithTracer(recordingTracer(&tx, &name))), middleware.GinRecovery(middleware.WithPanicLogger(logger)))
+	r.GET("/users/:id", func(c *gin.Context) {
+		panic("boom")
+	})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/12", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, 1, driver.Count, "panic should be logged")
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome(), "transaction should be marked as failed")
+	require.Contains(t, tx.Attrs, logging.NewAttr("statusCode", http.StatusInternalServerError))
+}
@@ -13,6 +13,7 @@ type Driver struct {
 	Count int
 }
 
+// Log passes the entry to LogFn as is. Like any driver, LogFn must clone the entry to keep it after Log returns.
 func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
 	d.Count++
 	if d.LogFn != nil {
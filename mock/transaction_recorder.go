@@ -2,6 +2,7 @@ package mock
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/silvan-talos/tlp/logging"
 	"github.com/silvan-talos/tlp/transaction"
@@ -9,6 +10,12 @@ import (
 
 type TransactionRecorder struct {
 	RecordTransactionFn func(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context)
+	EndTransactionFn    func(ctx context.Context, tx *transaction.Transaction)
+	RecordSpanFn        func(ctx context.Context, tx *transaction.Transaction, name, spanType string) (*transaction.Span, context.Context)
+	EndSpanFn           func(ctx context.Context, span *transaction.Span)
+	RecordErrorFn       func(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error)
+
+	SpanCount int
 }
 
 func (tr *TransactionRecorder) RecordTransaction(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context) {
@@ -17,15 +24,38 @@ func (tr *TransactionRecorder) RecordTransaction(ctx context.Context, name, tran
 	}
 	return &transaction.Transaction{
 		TraceID: "test-trace",
+		ID:      "test-transaction",
 		Attrs: []logging.Attr{
-			{
-				Key:   "name",
-				Value: name,
-			},
-			{
-				Key:   "type",
-				Value: transactionType,
-			},
+			logging.String("name", name),
+			logging.String("type", transactionType),
 		},
 	}, context.WithValue(ctx, "env", "test")
 }
+
+func (tr *TransactionRecorder) EndTransaction(ctx context.Context, tx *transaction.Transaction) {
+	if tr.EndTransactionFn != nil {
+		tr.EndTransactionFn(ctx, tx)
+	}
+}
+
+func (tr *TransactionRecorder) RecordSpan(ctx context.Context, tx *transaction.Transaction, name, spanType string) (*transaction.Span, context.Context) {
+	tr.SpanCount++
+	if tr.RecordSpanFn != nil {
+		return tr.RecordSpanFn(ctx, tx, name, spanType)
+	}
+	return &transaction.Span{
+		ID: fmt.Sprintf("test-span-%d", tr.SpanCount),
+	}, ctx
+}
+
+func (tr *TransactionRecorder) EndSpan(ctx context.Context, span *transaction.Span) {
+	if tr.EndSpanFn != nil {
+		tr.EndSpanFn(ctx, span)
+	}
+}
+
+func (tr *TransactionRecorder) RecordError(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error) {
+	if tr.RecordErrorFn != nil {
+		tr.RecordErrorFn(ctx, tx, txErr)
+	}
+}
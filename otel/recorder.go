@@ -0,0 +1,197 @@
+// Package otel is a trace recorder integration with OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+const instrumentationName = "github.com/silvan-talos/tlp"
+
+// Recorder is the implementation of an OpenTelemetry trace recorder.
+// Transactions and spans are recorded as OTel spans, with their attrs mapped to span attributes.
+type Recorder struct {
+	tracer trace.Tracer
+	// sampleErrors marks the transactions recording an error as sampled.
+	sampleErrors bool
+}
+
+type Option func(*Recorder)
+
+// WithErrorSampling marks the transactions recording an error as sampled, so their next entries are logged and
+// the flag is propagated to the downstream services. The sampling decision of the OTel span itself is made by the
+// provider sampler when it starts.
+func WithErrorSampling() Option {
+	return func(r *Recorder) {
+		r.sampleErrors = true
+	}
+}
+
+func NewRecorder(provider trace.TracerProvider, opts ...Option) *Recorder {
+	r := &Recorder{
+		tracer: provider.Tracer(instrumentationName),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// NewTracerProvider creates an SDK tracer provider describing the service configured in cfg.
+// If a server URL is configured, spans are exported to it using OTLP over HTTP, otherwise exporters can be
+// attached later with RegisterSpanProcessor.
+func NewTracerProvider(cfg config.TransactionConfig) (*sdktrace.TracerProvider, error) {
+	var attrs []attribute.KeyValue
+	if cfg.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceName(cfg.ServiceName))
+	}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.ServiceVersion))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(cfg.Environment))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("create resource: %w", err)
+	}
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if cfg.SampleRate != nil {
+		// continued traces keep the decision of their remote parent
+		opts = append(opts, sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*cfg.SampleRate))))
+	}
+	if cfg.ServerURL != "" {
+		exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.ServerURL))
+		if err != nil {
+			return nil, fmt.Errorf("create exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	return sdktrace.NewTracerProvider(opts...), nil
+}
+
+func (r *Recorder) RecordTransaction(ctx context.Context, name, transactionType string) (*transaction.Transaction, context.Context) {
+	parent, remote := transaction.TraceParentFromContext(ctx)
+	if remote {
+		if sc := spanContext(parent); sc.IsValid() {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+	ctx, span := r.tracer.Start(ctx, name, trace.WithAttributes(attribute.String("type", transactionType)))
+	sc := span.SpanContext()
+	tx := &transaction.Transaction{
+		TraceID:    sc.TraceID().String(),
+		ID:         sc.SpanID().String(),
+		TraceState: sc.TraceState().String(),
+	}
+	tx.SetSampled(sc.IsSampled())
+	if remote {
+		tx.ParentID = parent.ParentID
+	}
+	return tx, ctx
+}
+
+// EndTransaction sets the span attributes and status and ends the span.
+func (r *Recorder) EndTransaction(ctx context.Context, tx *transaction.Transaction) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attributes(tx.GetAttrs())...)
+	switch tx.Outcome() {
+	case transaction.OutcomeSuccess:
+		span.SetStatus(codes.Ok, "")
+	case transaction.OutcomeFailure:
+		span.SetStatus(codes.Error, tx.Result())
+	}
+	span.End()
+}
+
+func (r *Recorder) RecordSpan(ctx context.Context, tx *transaction.Transaction, name, spanType string) (*transaction.Span, context.Context) {
+	ctx, span := r.tracer.Start(ctx, name, trace.WithAttributes(attribute.String("type", spanType)))
+	return &transaction.Span{
+		ID: span.SpanContext().SpanID().String(),
+	}, ctx
+}
+
+func (r *Recorder) EndSpan(ctx context.Context, span *transaction.Span) {
+	otelSpan := trace.SpanFromContext(ctx)
+	otelSpan.SetAttributes(attributes(span.Attrs)...)
+	otelSpan.End()
+}
+
+// RecordError adds the error as an exception event of the transaction span and marks the span as failed.
+func (r *Recorder) RecordError(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error) {
+	if r.sampleErrors {
+		tx.SetSampled(true)
+	}
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(txErr.Err, trace.WithTimestamp(txErr.Time))
+	span.SetStatus(codes.Error, txErr.Message)
+}
+
+// spanContext converts a W3C trace parent into a remote OTel span context.
+// An invalid trace parent results in an invalid span context, which starts a new trace.
+func spanContext(parent transaction.TraceParent) trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex(parent.TraceID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(parent.ParentID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	cfg := trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Remote:  true,
+	}
+	if parent.Sampled {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	if state, err := trace.ParseTraceState(parent.State); err == nil {
+		cfg.TraceState = state
+	}
+	return trace.NewSpanContext(cfg)
+}
+
+// attributes maps tlp attrs to OTel attributes, flattening groups into dotted keys.
+// Values without a matching attribute type are formatted as strings.
+func attributes(attrs []logging.Attr) []attribute.KeyValue {
+	attrs = logging.Flatten(logging.ResolveAttrs(attrs))
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kvs = append(kvs, attributeKV(attr.Key, attr.Value.Any()))
+	}
+	return kvs
+}
+
+func attributeKV(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,106 @@
+package otel_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/otel"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func newTestTracer() (*transaction.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return transaction.NewTracer(otel.NewRecorder(provider)), exporter
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("record transaction and span", recordTransactionAndSpan)
+	t.Run("continue remote trace", continueRemoteTrace)
+	t.Run("record error", recordError)
+	t.Run("sample errors", sampleErrors)
+}
+
+func recordTransactionAndSpan(t *testing.T) {
+	t.Parallel()
+
+	tracer, exporter := newTestTracer()
+	tx, ctx := tracer.StartTransaction(context.Background(), "GET /users/:id", "request", logging.NewAttr("userID", 12))
+	span, _ := transaction.StartSpan(ctx, "select user", "db", logging.NewAttr("table", "users"))
+	require.Empty(t, exporter.GetSpans(), "spans should be exported only once ended")
+	span.End()
+	tx.SetOutcome(transaction.OutcomeSuccess)
+	tx.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2, "transaction and span should be exported")
+	dbSpan, txSpan := spans[0], spans[1]
+	require.Equal(t, "GET /users/:id", txSpan.Name)
+	require.Equal(t, tx.TraceID, txSpan.SpanContext.TraceID().String(), "trace ID should match")
+	require.Equal(t, tx.ID, txSpan.SpanContext.SpanID().String(), "transaction ID should match")
+	require.True(t, tx.Sampled(), "sampled flag should be set")
+	require.Contains(t, txSpan.Attributes, attribute.Int("userID", 12), "attrs should be mapped to span attributes")
+	require.Contains(t, txSpan.Attributes, attribute.String("type", "request"))
+	require.Equal(t, codes.Ok, txSpan.Status.Code)
+
+	require.Equal(t, "select user", dbSpan.Name)
+	require.Equal(t, span.ID, dbSpan.SpanContext.SpanID().String(), "span ID should match")
+	require.Equal(t, tx.ID, dbSpan.Parent.SpanID().String(), "span should be a child of the transaction")
+	require.Contains(t, dbSpan.Attributes, attribute.String("table", "users"))
+}
+
+func continueRemoteTrace(t *testing.T) {
+	t.Parallel()
+
+	tracer, exporter := newTestTracer()
+	ctx := transaction.ContextWithTraceParent(context.Background(), transaction.TraceParent{
+		TraceID:  "4bf92f3577b34da6a3ce929d0e0e4736",
+		ParentID: "00f067aa0ba902b7",
+		Sampled:  true,
+		State:    "vendor=value",
+	})
+	tx, _ := tracer.StartTransaction(ctx, "test", "unit-test")
+	tx.End()
+
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tx.TraceID, "remote trace should be continued")
+	require.Equal(t, "00f067aa0ba902b7", tx.ParentID, "remote parent should be recorded")
+	require.Equal(t, "vendor=value", tx.TraceState, "trace state should be propagated")
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.True(t, spans[0].Parent.IsRemote(), "span parent should be remote")
+	require.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
+
+func recordError(t *testing.T) {
+	t.Parallel()
+
+	tracer, exporter := newTestTracer()
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	tx.RecordError(errors.New("user not found"))
+	tx.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status.Code, "span should be marked as failed")
+	require.Len(t, spans[0].Events, 1, "error should be recorded as an event")
+	require.Equal(t, "exception", spans[0].Events[0].Name)
+}
+
+func sampleErrors(t *testing.T) {
+	t.Parallel()
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	tracer := transaction.NewTracer(otel.NewRecorder(provider, otel.WithErrorSampling()))
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	require.False(t, tx.Sampled(), "provider sampler should decide new traces")
+	tx.RecordError(errors.New("failed"))
+	require.True(t, tx.Sampled(), "transactions recording an error should be sampled")
+}
@@ -0,0 +1,214 @@
+// Package otlp provides a driver that ships log entries as OTLP log records, over gRPC or HTTP/protobuf.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+const (
+	instrumentationName = "github.com/silvan-talos/tlp"
+
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http/protobuf"
+)
+
+// transactionAttrPrefix namespaces the transaction attrs, so they don't collide with the entry attrs.
+const transactionAttrPrefix = "transaction."
+
+// Driver converts entries to OTLP log records and exports them in batches.
+// Records are correlated to the trace and span found in the entry.
+type Driver struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+// NewDriver creates a driver exporting to the collector configured in cfg.
+// Endpoint settings missing from cfg fall back to the OTEL_EXPORTER_OTLP_* environment variables.
+func NewDriver(cfg config.OTLPConfig) (*Driver, error) {
+	exporter, err := newExporter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("create exporter: %w", err)
+	}
+	return NewDriverWithExporter(exporter, cfg), nil
+}
+
+// NewDriverWithExporter creates a driver using a custom exporter. Only the batching settings of cfg are used.
+func NewDriverWithExporter(exporter sdklog.Exporter, cfg config.OTLPConfig) *Driver {
+	var opts []sdklog.BatchProcessorOption
+	if cfg.FlushInterval > 0 {
+		opts = append(opts, sdklog.WithExportInterval(cfg.FlushInterval))
+	}
+	if cfg.QueueSize > 0 {
+		opts = append(opts, sdklog.WithMaxQueueSize(cfg.QueueSize))
+	}
+	if cfg.BatchSize > 0 {
+		opts = append(opts, sdklog.WithExportMaxBatchSize(cfg.BatchSize))
+	}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, opts...)))
+	return &Driver{
+		provider: provider,
+		logger:   provider.Logger(instrumentationName),
+	}
+}
+
+func newExporter(cfg config.OTLPConfig) (sdklog.Exporter, error) {
+	ctx := context.Background()
+	switch cfg.Protocol {
+	case ProtocolGRPC:
+		var opts []otlploggrpc.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploggrpc.WithEndpointURL(cfg.Endpoint))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	case ProtocolHTTP, "":
+		var opts []otlploghttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpointURL(cfg.Endpoint))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported protocol: %s", cfg.Protocol)
+	}
+}
+
+func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
+	var record otellog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(severity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(otellog.StringValue(entry.Message))
+	for _, attr := range logging.ResolveAttrs(entry.Attrs) {
+		record.AddAttributes(otellog.KeyValue{Key: attr.Key, Value: attrValue(attr.Value)})
+	}
+	for _, attr := range logging.ResolveAttrs(entry.TransactionAttrs) {
+		record.AddAttributes(otellog.KeyValue{Key: transactionAttrPrefix + attr.Key, Value: attrValue(attr.Value)})
+	}
+	d.logger.Emit(traceContext(ctx, entry), record)
+}
+
+// Flush exports all the buffered records.
+func (d *Driver) Flush(ctx context.Context) error {
+	return d.provider.ForceFlush(ctx)
+}
+
+// Close flushes the buffered records and shuts the exporter down.
+func (d *Driver) Close(ctx context.Context) error {
+	return d.provider.Shutdown(ctx)
+}
+
+// traceContext stores the entry trace and span IDs in ctx, where the OTel SDK looks for them.
+// Entries without a W3C compatible TraceID are not correlated.
+func traceContext(ctx context.Context, entry logging.Entry) context.Context {
+	traceID, err := trace.TraceIDFromHex(entry.TraceID)
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(entry.SpanID)
+	if err != nil {
+		// fall back to the transaction itself
+		spanID, _ = trace.SpanIDFromHex(transaction.FromContext(ctx).ID)
+	}
+	return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+}
+
+// severity maps a level to the OTel severity number. Default levels map to the base severity of their range,
+// custom levels are placed in between.
+func severity(level logging.Level) otellog.Severity {
+	s := int(level) + int(otellog.SeverityInfo)
+	switch {
+	case s < int(otellog.SeverityTrace1):
+		return otellog.SeverityTrace1
+	case s > int(otellog.SeverityFatal4):
+		return otellog.SeverityFatal4
+	}
+	return otellog.Severity(s)
+}
+
+// attrValue converts v to an OpenTelemetry value, keeping groups as maps.
+func attrValue(v logging.Value) otellog.Value {
+	switch v.Kind() {
+	case logging.KindString:
+		return otellog.StringValue(v.String())
+	case logging.KindInt64:
+		return otellog.Int64Value(v.Int64())
+	case logging.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			return otellog.Int64Value(int64(u))
+		}
+		return otellog.StringValue(v.String())
+	case logging.KindFloat64:
+		return otellog.Float64Value(v.Float64())
+	case logging.KindBool:
+		return otellog.BoolValue(v.Bool())
+	case logging.KindGroup:
+		kvs := make([]otellog.KeyValue, 0, len(v.Group()))
+		for _, attr := range v.Group() {
+			kvs = append(kvs, otellog.KeyValue{Key: attr.Key, Value: attrValue(attr.Value)})
+		}
+		return otellog.MapValue(kvs...)
+	default:
+		return value(v.Any())
+	}
+}
+
+func value(v any) otellog.Value {
+	switch v := v.(type) {
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case int:
+		return otellog.IntValue(v)
+	case int8:
+		return otellog.Int64Value(int64(v))
+	case int16:
+		return otellog.Int64Value(int64(v))
+	case int32:
+		return otellog.Int64Value(int64(v))
+	case int64:
+		return otellog.Int64Value(v)
+	case uint8:
+		return otellog.Int64Value(int64(v))
+	case uint16:
+		return otellog.Int64Value(int64(v))
+	case uint32:
+		return otellog.Int64Value(int64(v))
+	case float32:
+		return otellog.Float64Value(float64(v))
+	case float64:
+		return otellog.Float64Value(v)
+	case []byte:
+		return otellog.BytesValue(v)
+	case time.Time:
+		return otellog.StringValue(v.Format(time.RFC3339Nano))
+	case error:
+		return otellog.StringValue(v.Error())
+	case fmt.Stringer:
+		return otellog.StringValue(v.String())
+	default:
+		return otellog.StringValue(fmt.Sprintf("%+v", v))
+	}
+}
@@ -0,0 +1,187 @@
+package otlp_test
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/silvan-talos/tlp/config"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/otlp"
+)
+
+// receiver is an in-process OTLP collector keeping the received log records.
+type receiver struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu      sync.Mutex
+	records []*logspb.LogRecord
+}
+
+func (r *receiver) Export(ctx context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rl := range req.ResourceLogs {
+		for _, sl := range rl.ScopeLogs {
+			r.records = append(r.records, sl.LogRecords...)
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var exportReq collogspb.ExportLogsServiceRequest
+	if err = proto.Unmarshal(body, &exportReq); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	resp, _ := r.Export(req.Context(), &exportReq)
+	out, _ := proto.Marshal(resp)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(out)
+}
+
+func (r *receiver) get() []*logspb.LogRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.records
+}
+
+func TestDriver(t *testing.T) {
+	t.Run("export over http", exportOverHTTP)
+	t.Run("export over grpc", exportOverGRPC)
+	t.Run("batching", batching)
+}
+
+func exportOverHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{}
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	driver, err := otlp.NewDriver(config.OTLPConfig{
+		Endpoint: ts.URL + "/v1/logs",
+		Protocol: otlp.ProtocolHTTP,
+	})
+	require.NoError(t, err, "driver should be created")
+	logAndCheck(t, driver, r)
+}
+
+func exportOverGRPC(t *testing.T) {
+	t.Parallel()
+
+	r := &receiver{}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, r)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	driver, err := otlp.NewDriver(config.OTLPConfig{
+		Endpoint: "http://" + lis.Addr().String(),
+		Protocol: otlp.ProtocolGRPC,
+	})
+	require.NoError(t, err, "driver should be created")
+	logAndCheck(t, driver, r)
+}
+
+func logAndCheck(t *testing.T, driver *otlp.Driver, r *receiver) {
+	t.Helper()
+
+	driver.Log(context.Background(), logging.Entry{
+		Time:             time.Now(),
+		Message:          "user created",
+		Level:            logging.LevelWarn,
+		Attrs:            []logging.Attr{logging.NewAttr("id", 12), logging.NewAttr("name", "John")},
+		TraceID:          "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:           "00f067aa0ba902b7",
+		TransactionAttrs: []logging.Attr{logging.NewAttr("requestPath", "/users")},
+	})
+	require.NoError(t, driver.Close(context.Background()), "driver should be closed without errors")
+
+	records := r.get()
+	require.Len(t, records, 1, "record should be exported")
+	record := records[0]
+	require.Equal(t, "user created", record.Body.GetStringValue())
+	require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.SeverityNumber, "severity should be mapped")
+	require.Equal(t, "WARN", record.SeverityText)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(record.TraceId), "record should be correlated to the trace")
+	require.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(record.SpanId), "record should be correlated to the span")
+	attrs := make(map[string]any)
+	for _, kv := range record.Attributes {
+		switch v := kv.Value.Value.(type) {
+		case *commonpb.AnyValue_StringValue:
+			attrs[kv.Key] = v.StringValue
+		case *commonpb.AnyValue_IntValue:
+			attrs[kv.Key] = v.IntValue
+		}
+	}
+	require.Equal(t, map[string]any{"id": int64(12), "name": "John", "transaction.requestPath": "/users"}, attrs)
+}
+
+// countingExporter records the size of every exported batch.
+type countingExporter struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (e *countingExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.batches = append(e.batches, len(records))
+	return nil
+}
+
+func (e *countingExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *countingExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func batching(t *testing.T) {
+	t.Parallel()
+
+	exporter := &countingExporter{}
+	driver := otlp.NewDriverWithExporter(exporter, config.OTLPConfig{
+		FlushInterval: time.Hour,
+		BatchSize:     2,
+	})
+	for i := 0; i < 5; i++ {
+		driver.Log(context.Background(), logging.Entry{Time: time.Now(), Message: "test", Level: logging.LevelInfo})
+	}
+	require.NoError(t, driver.Flush(context.Background()))
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	total := 0
+	for _, size := range exporter.batches {
+		require.LessOrEqual(t, size, 2, "batches should not exceed the configured size")
+		total += size
+	}
+	require.Equal(t, 5, total, "all records should be exported")
+}
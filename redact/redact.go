@@ -0,0 +1,64 @@
+// Package redact provides the rules masking sensitive data, like passwords or emails, before entries reach the drivers.
+package redact
+
+import (
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// Mask replaces the redacted values.
+const Mask = "[REDACTED]"
+
+// Redactor rewrites attributes holding sensitive data.
+type Redactor interface {
+	// Redact returns the attribute to log instead of attr and true if attr holds sensitive data,
+	// or attr and false otherwise.
+	Redact(attr logging.Attr) (logging.Attr, bool)
+}
+
+// RedactorFunc adapts a function to the Redactor interface.
+type RedactorFunc func(attr logging.Attr) (logging.Attr, bool)
+
+func (f RedactorFunc) Redact(attr logging.Attr) (logging.Attr, bool) {
+	return f(attr)
+}
+
+// Chain applies the redactors in order, each one receiving the result of the previous ones.
+type Chain []Redactor
+
+func (c Chain) Redact(attr logging.Attr) (logging.Attr, bool) {
+	redacted := false
+	for _, r := range c {
+		var changed bool
+		if attr, changed = r.Redact(attr); changed {
+			redacted = true
+		}
+	}
+	return attr, redacted
+}
+
+// Attrs applies r to attrs and to the members of the groups left untouched by r.
+// The given slice and groups are not modified; they are returned as is if nothing is redacted.
+func Attrs(r Redactor, attrs []logging.Attr) []logging.Attr {
+	var redacted []logging.Attr
+	for i, attr := range attrs {
+		result, changed := r.Redact(attr)
+		if !changed && attr.Value.Kind() == logging.KindGroup {
+			members := attr.Value.Group()
+			if redactedMembers := Attrs(r, members); len(members) > 0 && &redactedMembers[0] != &members[0] {
+				result, changed = logging.Group(attr.Key, redactedMembers...), true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if redacted == nil {
+			redacted = make([]logging.Attr, len(attrs))
+			copy(redacted, attrs)
+		}
+		redacted[i] = result
+	}
+	if redacted == nil {
+		return attrs
+	}
+	return redacted
+}
@@ -0,0 +1,157 @@
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// DefaultKeys are the keys of the attributes usually holding secrets.
+var DefaultKeys = []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "api_key",
+	"authorization", "cookie", "set-cookie"}
+
+// Patterns of common sensitive values, usable by name in the configuration.
+var (
+	EmailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	CardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	JWTPattern        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+var namedPatterns = map[string]*regexp.Regexp{
+	"email":       EmailPattern,
+	"card_number": CardNumberPattern,
+	"jwt":         JWTPattern,
+}
+
+type Options struct {
+	// Keys lists the keys of the attributes whose whole value is masked, compared case-insensitively.
+	Keys []string
+	// Patterns lists the parts of string values to mask.
+	Patterns []*regexp.Regexp
+	// Mask replaces the redacted values. Defaults to Mask.
+	Mask string
+}
+
+// Rules is a Redactor masking the values of denylisted keys, the parts of the values matching the patterns and the
+// struct fields tagged with `log:"redact"`. The patterns apply to strings, errors and fmt.Stringers, other values
+// are not formatted; group members are handled by Attrs.
+type Rules struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+func New(opts Options) *Rules {
+	r := &Rules{
+		keys:     make(map[string]struct{}, len(opts.Keys)),
+		patterns: opts.Patterns,
+		mask:     opts.Mask,
+	}
+	if r.mask == "" {
+		r.mask = Mask
+	}
+	for _, key := range opts.Keys {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	return r
+}
+
+// CompilePatterns compiles the patterns, which are either regular expressions or the names of the built-in
+// patterns: email, card_number and jwt. The valid patterns are returned even if some are invalid, along with an error
+// listing the invalid ones, so a single typo does not disable the others.
+func CompilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	var errs []error
+	for _, pattern := range patterns {
+		if re, ok := namedPatterns[pattern]; ok {
+			compiled = append(compiled, re)
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("compile redaction pattern %q: %w", pattern, err))
+			continue
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, errors.Join(errs...)
+}
+
+func (r *Rules) Redact(attr logging.Attr) (logging.Attr, bool) {
+	return r.redact(attr, 0)
+}
+
+// redact redacts attr, found depth structs deep in a redacted struct.
+func (r *Rules) redact(attr logging.Attr, depth int) (logging.Attr, bool) {
+	if _, ok := r.keys[strings.ToLower(attr.Key)]; ok {
+		return logging.String(attr.Key, r.mask), true
+	}
+	switch attr.Value.Kind() {
+	case logging.KindString:
+		if value, ok := r.redactString(attr.Value.String()); ok {
+			return logging.String(attr.Key, value), true
+		}
+	case logging.KindAny:
+		if value, ok := r.redactStruct(attr.Value.Any(), depth); ok {
+			return logging.Attr{Key: attr.Key, Value: value}, true
+		}
+		if value, ok := r.redactFormatted(attr.Value); ok {
+			return logging.Attr{Key: attr.Key, Value: value}, true
+		}
+	}
+	return attr, false
+}
+
+// redactFormatted applies the patterns to the text the drivers would write for errors and fmt.Stringers. Errors keep
+// their structure, fmt.Stringers are replaced by their redacted text. Other values are left alone, so they are not
+// formatted for every entry.
+func (r *Rules) redactFormatted(v logging.Value) (logging.Value, bool) {
+	if len(r.patterns) == 0 {
+		return v, false
+	}
+	switch value := v.Any().(type) {
+	case error:
+		details, redacted := r.redactErrorDetails(logging.NewErrorDetails(value))
+		return logging.AnyValue(details), redacted
+	case fmt.Stringer:
+		if text, ok := r.redactString(value.String()); ok {
+			return logging.StringValue(text), true
+		}
+	}
+	return v, false
+}
+
+func (r *Rules) redactErrorDetails(details logging.ErrorDetails) (logging.ErrorDetails, bool) {
+	message, redacted := r.redactString(details.Message)
+	details.Message = message
+	if len(details.Fields) > 0 {
+		if fields := Attrs(r, details.Fields); &fields[0] != &details.Fields[0] {
+			details.Fields, redacted = fields, true
+		}
+	}
+	if len(details.Causes) > 0 {
+		causes := make([]logging.ErrorDetails, len(details.Causes))
+		for i, cause := range details.Causes {
+			var changed bool
+			if causes[i], changed = r.redactErrorDetails(cause); changed {
+				redacted = true
+			}
+		}
+		details.Causes = causes
+	}
+	return details, redacted
+}
+
+func (r *Rules) redactString(s string) (string, bool) {
+	redacted := false
+	for _, re := range r.patterns {
+		if re.MatchString(s) {
+			s = re.ReplaceAllLiteralString(s, r.mask)
+			redacted = true
+		}
+	}
+	return s, redacted
+}
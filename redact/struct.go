@@ -0,0 +1,115 @@
+package redact
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// maxDepth limits the nesting of the redacted structs, so values referencing themselves are not walked forever.
+const maxDepth = 16
+
+// structFields caches the fields of the struct types, nil for the types without a field tagged `log:"redact"`.
+var structFields sync.Map // map[reflect.Type][]structField
+
+type structField struct {
+	index  int
+	name   string
+	redact bool
+	// nested is set for the struct fields holding redacted fields themselves
+	nested bool
+}
+
+// redactStruct converts the structs, or pointers to structs, having fields tagged with `log:"redact"` to groups of
+// their exported fields, the tagged ones being masked. Fields are named after their json tag, like encoding/json does.
+// The structs nested deeper than maxDepth are masked as a whole.
+func (r *Rules) redactStruct(value any, depth int) (logging.Value, bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return logging.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct || fieldsOf(v.Type()) == nil {
+		return logging.Value{}, false
+	}
+	return r.structValue(v, depth), true
+}
+
+func (r *Rules) structValue(v reflect.Value, depth int) logging.Value {
+	if depth >= maxDepth {
+		return logging.StringValue(r.mask)
+	}
+	fields := fieldsOf(v.Type())
+	attrs := make([]logging.Attr, 0, len(fields))
+	for _, field := range fields {
+		fv := v.Field(field.index)
+		switch {
+		case field.redact:
+			attrs = append(attrs, logging.String(field.name, r.mask))
+		case field.nested:
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() != reflect.Struct {
+				attrs = append(attrs, logging.Any(field.name, nil))
+				continue
+			}
+			attrs = append(attrs, logging.Attr{Key: field.name, Value: r.structValue(fv, depth+1)})
+		default:
+			attr, _ := r.redact(logging.Any(field.name, fv.Interface()), depth+1)
+			attrs = append(attrs, attr)
+		}
+	}
+	return logging.GroupValue(attrs...)
+}
+
+func fieldsOf(t reflect.Type) []structField {
+	if cached, ok := structFields.Load(t); ok {
+		return cached.([]structField)
+	}
+	fields := collectFields(t, map[reflect.Type]bool{})
+	structFields.Store(t, fields)
+	return fields
+}
+
+// collectFields returns the exported fields of t, or nil if none of them is redacted, directly or through nested
+// structs. visiting guards against recursive types.
+func collectFields(t reflect.Type, visiting map[reflect.Type]bool) []structField {
+	if visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	var fields []structField
+	redacted := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		field := structField{index: i, name: name, redact: f.Tag.Get("log") == "redact"}
+		if !field.redact {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			field.nested = ft.Kind() == reflect.Struct && collectFields(ft, visiting) != nil
+		}
+		redacted = redacted || field.redact || field.nested
+		fields = append(fields, field)
+	}
+	if !redacted {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,330 @@
+// Package rotate provides a file writer that rotates the file based on size, and removes or compresses old backups.
+package rotate
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTimeFormat is the timestamp added to rotated file names, e.g. app-2024-07-15T23-24-21.000.log.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+const compressSuffix = ".gz"
+
+type Options struct {
+	Filename string
+	// MaxSize is the size in bytes after which the file is rotated. Zero disables size based rotation.
+	MaxSize int64
+	// MaxAge is the retention period of the rotated files. Zero keeps them regardless of age.
+	// Files are rotated on size only: expired backups are removed when the writer is created and at every rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep. Zero keeps all of them.
+	MaxBackups int
+	// Compress gzips the rotated files.
+	Compress bool
+	// LocalTime uses the local time instead of UTC in the rotated file names.
+	LocalTime bool
+	// OnError receives the errors of rotations and backup cleanups, which do not fail the writes.
+	// Defaults to printing them to stderr.
+	OnError func(err error)
+}
+
+// Writer is an io.WriteCloser writing to Options.Filename. It is safe for concurrent use.
+type Writer struct {
+	opts Options
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// cleanupMu serializes the cleanups, run in the background so writes are not blocked by compression.
+	cleanupMu sync.Mutex
+	cleanups  sync.WaitGroup
+}
+
+// NewWriter opens or creates the file in append mode.
+func NewWriter(opts Options) (*Writer, error) {
+	if opts.Filename == "" {
+		return nil, errors.New("missing file name")
+	}
+	w := &Writer{opts: opts}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	w.startCleanup()
+	return w, nil
+}
+
+// Write writes p to the file, rotating it first if p would exceed the max size.
+// A single write larger than the max size is written to a fresh file. If the rotation fails, p is still written to
+// the current file and the error is passed to Options.OnError.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSize {
+		if err := w.rotate(); err != nil {
+			if w.file == nil {
+				return 0, err
+			}
+			w.reportError(err)
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the current file to a timestamped backup and starts a new one. The old backups are cleaned up in
+// the background.
+func (w *Writer) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file, so a file moved away by an external tool like logrotate gets recreated.
+func (w *Writer) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.close(); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+// ReopenOnSignal reopens the file whenever one of the signals is received, SIGHUP if none is given.
+// The returned function stops listening for signals.
+func (w *Writer) ReopenOnSignal(signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, signals...)
+	go func() {
+		for {
+			select {
+			case <-ch:
+				if err := w.Reopen(); err != nil {
+					fmt.Fprintln(os.Stderr, "reopen log file", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// Close closes the file and waits for the running cleanups to finish.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	err := w.close()
+	w.mu.Unlock()
+	w.cleanups.Wait()
+	return err
+}
+
+func (w *Writer) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.opts.Filename), 0755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	f, err := os.OpenFile(w.opts.Filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat file: %w", err)
+	}
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *Writer) close() error {
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// rotate renames the file and opens a new one. If the rename fails, the original file is reopened, so w.file is only
+// left nil if no file can be opened at all.
+func (w *Writer) rotate() error {
+	if err := w.close(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(w.opts.Filename); err == nil {
+		if err = os.Rename(w.opts.Filename, w.backupName()); err != nil {
+			return errors.Join(fmt.Errorf("rename file: %w", err), w.open())
+		}
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.startCleanup()
+	return nil
+}
+
+// startCleanup runs cleanup in the background, if the retention settings need one.
+func (w *Writer) startCleanup() {
+	if !w.opts.Compress && w.opts.MaxAge == 0 && w.opts.MaxBackups == 0 {
+		return
+	}
+	w.cleanups.Add(1)
+	go func() {
+		defer w.cleanups.Done()
+		w.cleanupMu.Lock()
+		defer w.cleanupMu.Unlock()
+		if err := w.cleanup(); err != nil {
+			w.reportError(err)
+		}
+	}()
+}
+
+func (w *Writer) reportError(err error) {
+	if w.opts.OnError != nil {
+		w.opts.OnError(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "rotate log file", err)
+}
+
+// backupName returns an unused backup file name for the current time.
+func (w *Writer) backupName() string {
+	dir, prefix, ext := w.nameParts()
+	t := w.now()
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+		_, errPlain := os.Stat(name)
+		_, errCompressed := os.Stat(name + compressSuffix)
+		if errors.Is(errPlain, os.ErrNotExist) && errors.Is(errCompressed, os.ErrNotExist) {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func (w *Writer) now() time.Time {
+	if w.opts.LocalTime {
+		return time.Now()
+	}
+	return time.Now().UTC()
+}
+
+// nameParts splits the file name into directory, backup prefix and extension, e.g. logs, app-, .log.
+func (w *Writer) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.opts.Filename)
+	base := filepath.Base(w.opts.Filename)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+type backup struct {
+	path string
+	time time.Time
+}
+
+// cleanup compresses the rotated files and removes the ones exceeding the retention settings.
+func (w *Writer) cleanup() error {
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, b := range backups {
+		expired := w.opts.MaxAge > 0 && time.Since(b.time) > w.opts.MaxAge
+		if expired || (w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups) {
+			errs = append(errs, os.Remove(b.path))
+			continue
+		}
+		if w.opts.Compress && !strings.HasSuffix(b.path, compressSuffix) {
+			errs = append(errs, compress(b.path))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// backups lists the rotated files, newest first.
+func (w *Writer) backups() ([]backup, error) {
+	dir, prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read log directory: %w", err)
+	}
+	loc := time.UTC
+	if w.opts.LocalTime {
+		loc = time.Local
+	}
+	var backups []backup
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), compressSuffix)
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		t, err := time.ParseInLocation(backupTimeFormat, ts, loc)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, e.Name()), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+	return backups, nil
+}
+
+func compress(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+compressSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("create compressed backup: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path + compressSuffix)
+		}
+	}()
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("compress backup: %w", err)
+	}
+	if err = gz.Close(); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("compress backup: %w", err)
+	}
+	if err = dst.Close(); err != nil {
+		return fmt.Errorf("close compressed backup: %w", err)
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
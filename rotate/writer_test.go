@@ -0,0 +1,170 @@
+package rotate_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/rotate"
+)
+
+func TestWriter(t *testing.T) {
+	t.Run("rotate on size", rotateOnSize)
+	t.Run("max backups", maxBackups)
+	t.Run("max age", maxAge)
+	t.Run("compress backups", compressBackups)
+	t.Run("cleanup error does not fail writes", cleanupErrorDoesNotFailWrites)
+}
+
+// listFiles returns the sorted file names found in dir.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func rotateOnSize(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := rotate.NewWriter(rotate.Options{Filename: filepath.Join(dir, "app.log"), MaxSize: 10})
+	require.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("12345\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("6789\n"))
+	require.NoError(t, err)
+	require.Len(t, listFiles(t, dir), 2, "file should be rotated once it exceeds the max size")
+	require.Equal(t, "6789\n", readFile(t, filepath.Join(dir, "app.log")), "new entries should go to a fresh file")
+
+	files := listFiles(t, dir)
+	require.Regexp(t, `^app-\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}\.log$`, files[0], "backup should be timestamped")
+	require.Equal(t, "12345\n", readFile(t, filepath.Join(dir, files[0])))
+}
+
+func maxBackups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := rotate.NewWriter(rotate.Options{Filename: filepath.Join(dir, "app.log"), MaxSize: 5, MaxBackups: 2})
+	require.NoError(t, err)
+	defer w.Close()
+
+	for _, line := range []string{"one\n", "two\n", "three\n", "four\n"} {
+		_, err = w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	// wait for the background cleanups
+	require.NoError(t, w.Close())
+	files := listFiles(t, dir)
+	require.Len(t, files, 3, "only the newest backups should be kept")
+	require.Equal(t, "two\n", readFile(t, filepath.Join(dir, files[0])))
+	require.Equal(t, "three\n", readFile(t, filepath.Join(dir, files[1])))
+	require.Equal(t, "four\n", readFile(t, filepath.Join(dir, "app.log")))
+}
+
+func maxAge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	old := filepath.Join(dir, "app-"+time.Now().UTC().Add(-48*time.Hour).Format("2006-01-02T15-04-05.000")+".log")
+	require.NoError(t, os.WriteFile(old, []byte("old\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.log"), []byte("other\n"), 0644))
+
+	w, err := rotate.NewWriter(rotate.Options{Filename: filepath.Join(dir, "app.log"), MaxAge: 24 * time.Hour})
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.Write([]byte("current\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Rotate())
+	require.NoError(t, w.Close())
+
+	files := listFiles(t, dir)
+	require.Len(t, files, 3, "expired backups should be removed")
+	require.NotContains(t, files, filepath.Base(old))
+	require.Contains(t, files, "other.log", "unrelated files should not be touched")
+}
+
+func compressBackups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w, err := rotate.NewWriter(rotate.Options{Filename: filepath.Join(dir, "app.log"), Compress: true, LocalTime: true})
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.Write([]byte("compressed\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Rotate())
+	require.NoError(t, w.Close())
+
+	files := listFiles(t, dir)
+	require.Len(t, files, 2)
+	require.True(t, strings.HasSuffix(files[0], ".log.gz"), "backup should be compressed")
+	f, err := os.Open(filepath.Join(dir, files[0]))
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	require.Equal(t, "compressed\n", string(content))
+}
+
+func cleanupErrorDoesNotFailWrites(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// a directory in place of the compressed backup makes the compression fail
+	backup := filepath.Join(dir, "app-"+time.Now().UTC().Add(-time.Hour).Format("2006-01-02T15-04-05.000")+".log")
+	require.NoError(t, os.WriteFile(backup, []byte("old\n"), 0644))
+	require.NoError(t, os.Mkdir(backup+".gz", 0755))
+
+	var mu sync.Mutex
+	var errs []error
+	w, err := rotate.NewWriter(rotate.Options{
+		Filename: filepath.Join(dir, "app.log"),
+		MaxSize:  5,
+		Compress: true,
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	require.NoError(t, err)
+	for _, line := range []string{"one\n", "two\n"} {
+		n, err := w.Write([]byte(line))
+		require.NoError(t, err, "write should not fail because of the cleanup")
+		require.Equal(t, len(line), n)
+	}
+	require.NoError(t, w.Close())
+
+	require.Equal(t, "two\n", readFile(t, filepath.Join(dir, "app.log")))
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, errs, "cleanup errors should be reported")
+}
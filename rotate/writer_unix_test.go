@@ -0,0 +1,39 @@
+//go:build unix
+
+package rotate_test
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/rotate"
+)
+
+func TestWriter_ReopenOnSignal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := rotate.NewWriter(rotate.Options{Filename: path})
+	require.NoError(t, err)
+	defer w.Close()
+	stop := w.ReopenOnSignal(syscall.SIGUSR1)
+	defer stop()
+
+	_, err = w.Write([]byte("before\n"))
+	require.NoError(t, err)
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond, "file should be recreated on signal")
+
+	_, err = w.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.Equal(t, "before\n", readFile(t, path+".1"))
+	require.Equal(t, "after\n", readFile(t, path))
+}
@@ -0,0 +1,226 @@
+// Package sample provides a driver wrapper limiting the entries repeating the same level and message.
+package sample
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+const defaultInterval = time.Second
+
+// SummaryMessage is the message of the entries reporting the suppressed entries.
+const SummaryMessage = "log entries suppressed"
+
+type Options struct {
+	// Interval is the period the sampling counters are reset at and the summaries are logged at. Defaults to 1s.
+	Interval time.Duration
+	// First is the number of entries with the same level and message kept per interval, before sampling applies.
+	First int
+	// Thereafter keeps every Mth entry once First is reached; 0 drops them all. Sampling is disabled if both First
+	// and Thereafter are 0.
+	Thereafter int
+	// Rate is the number of entries per second kept for each level and message, once sampled. 0 disables the limit.
+	Rate float64
+	// Burst is the number of entries logged at once before Rate applies. Defaults to Rate, at least 1.
+	Burst int
+	// KeepSampled exempts the entries logged within sampled transactions from the limits. Recorders sample every
+	// trace by default, so it is only useful along with a trace sample rate below 1.
+	KeepSampled bool
+}
+
+// driver is the log.Driver interface, declared here to avoid depending on the log package.
+type driver interface {
+	Log(ctx context.Context, entry logging.Entry)
+}
+
+type key struct {
+	level   logging.Level
+	message string
+}
+
+// counter tracks the entries of a level and message.
+type counter struct {
+	windowStart time.Time
+	count       int
+	tokens      float64
+	lastRefill  time.Time
+	lastSeen    time.Time
+}
+
+// Driver drops the entries exceeding the sampling and rate limits, timed by the entry times, and periodically logs
+// how many were suppressed. With Options.KeepSampled, entries of sampled transactions are always logged.
+type Driver struct {
+	next        driver
+	interval    time.Duration
+	first       int
+	thereafter  int
+	rate        float64
+	burst       float64
+	keepSampled bool
+
+	mu          sync.Mutex
+	counters    map[key]*counter
+	sampledOut  uint64
+	rateLimited uint64
+
+	stop    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// NewDriver wraps next and starts the summary ticker. Close must be called to release it.
+func NewDriver(next driver, opts Options) *Driver {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = max(1, int(opts.Rate))
+	}
+	d := &Driver{
+		next:        next,
+		interval:    opts.Interval,
+		first:       opts.First,
+		thereafter:  opts.Thereafter,
+		rate:        opts.Rate,
+		burst:       float64(opts.Burst),
+		keepSampled: opts.KeepSampled,
+		counters:    make(map[key]*counter),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
+	if d.keepSampled {
+		if sampled, _ := transaction.SampledFromContext(ctx); sampled {
+			d.next.Log(ctx, entry)
+			return
+		}
+	}
+	if d.allow(entry) {
+		d.next.Log(ctx, entry)
+	}
+}
+
+// allow reports whether the entry is within the limits, counting it otherwise.
+func (d *Driver) allow(entry logging.Entry) bool {
+	now := entry.Time
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	k := key{level: entry.Level, message: entry.Message}
+	c, ok := d.counters[k]
+	if !ok {
+		c = &counter{windowStart: now, tokens: d.burst, lastRefill: now}
+		d.counters[k] = c
+	}
+	c.lastSeen = now
+	if d.first > 0 || d.thereafter > 0 {
+		if now.Sub(c.windowStart) >= d.interval {
+			c.windowStart, c.count = now, 0
+		}
+		c.count++
+		if c.count > d.first && (d.thereafter == 0 || (c.count-d.first-1)%d.thereafter != 0) {
+			d.sampledOut++
+			return false
+		}
+	}
+	if d.rate > 0 {
+		if elapsed := now.Sub(c.lastRefill); elapsed > 0 {
+			c.tokens = min(d.burst, c.tokens+elapsed.Seconds()*d.rate)
+			c.lastRefill = now
+		}
+		if c.tokens < 1 {
+			d.rateLimited++
+			return false
+		}
+		c.tokens--
+	}
+	return true
+}
+
+// Suppressed returns the number of entries dropped since the last summary.
+func (d *Driver) Suppressed() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.sampledOut + d.rateLimited
+}
+
+// Flush logs the summary of the entries suppressed so far, then flushes the wrapped driver if it supports flushing.
+func (d *Driver) Flush(ctx context.Context) error {
+	d.summarize(time.Now())
+	if f, ok := d.next.(interface{ Flush(context.Context) error }); ok {
+		return f.Flush(ctx)
+	}
+	return nil
+}
+
+// Close stops the summary ticker and logs the last summary. The wrapped driver is closed as well if it supports
+// closing.
+func (d *Driver) Close(ctx context.Context) error {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.stopped:
+	}
+	d.summarize(time.Now())
+	if c, ok := d.next.(interface{ Close(context.Context) error }); ok {
+		return c.Close(ctx)
+	}
+	return nil
+}
+
+func (d *Driver) run() {
+	defer close(d.stopped)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case now := <-ticker.C:
+			d.summarize(now)
+			d.evict(now)
+		}
+	}
+}
+
+// summarize logs a warning with the number of entries suppressed since the previous summary, if any.
+func (d *Driver) summarize(now time.Time) {
+	d.mu.Lock()
+	sampledOut, rateLimited := d.sampledOut, d.rateLimited
+	d.sampledOut, d.rateLimited = 0, 0
+	d.mu.Unlock()
+	if sampledOut+rateLimited == 0 {
+		return
+	}
+	d.next.Log(context.Background(), logging.Entry{
+		Time:    now,
+		Message: SummaryMessage,
+		Level:   logging.LevelWarn,
+		Attrs: []logging.Attr{
+			logging.Uint64("suppressed", sampledOut+rateLimited),
+			logging.Uint64("sampled_out", sampledOut),
+			logging.Uint64("rate_limited", rateLimited),
+		},
+	})
+}
+
+// evict drops the counters of the messages not logged during the last interval, so they do not accumulate.
+func (d *Driver) evict(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for k, c := range d.counters {
+		if now.Sub(c.lastSeen) >= d.interval {
+			delete(d.counters, k)
+		}
+	}
+}
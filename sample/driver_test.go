@@ -0,0 +1,118 @@
+package sample_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/sample"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+// recordingDriver records the logged entries.
+type recordingDriver struct {
+	mu      sync.Mutex
+	entries []logging.Entry
+}
+
+func (d *recordingDriver) Log(ctx context.Context, entry logging.Entry) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = append(d.entries, entry)
+}
+
+func (d *recordingDriver) count(msg string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, entry := range d.entries {
+		if entry.Message == msg {
+			n++
+		}
+	}
+	return n
+}
+
+// logAt logs n entries with msg, spaced by step from start.
+func logAt(d *sample.Driver, ctx context.Context, start time.Time, step time.Duration, n int, msg string) {
+	for i := 0; i < n; i++ {
+		d.Log(ctx, logging.Entry{Time: start.Add(time.Duration(i) * step), Message: msg, Level: logging.LevelWarn})
+	}
+}
+
+func TestDriver_Sampling(t *testing.T) {
+	t.Parallel()
+
+	next := &recordingDriver{}
+	d := sample.NewDriver(next, sample.Options{Interval: time.Hour, First: 3, Thereafter: 10})
+	start := time.Now()
+
+	logAt(d, context.Background(), start, time.Millisecond, 100, "hot loop")
+	logAt(d, context.Background(), start, time.Millisecond, 2, "other")
+	// first 3, then the 4th, 14th, ..., 94th
+	require.Equal(t, 13, next.count("hot loop"))
+	require.Equal(t, 2, next.count("other"), "messages should be sampled separately")
+	require.EqualValues(t, 87, d.Suppressed())
+
+	logAt(d, context.Background(), start.Add(time.Hour), time.Millisecond, 3, "hot loop")
+	require.Equal(t, 16, next.count("hot loop"), "counters should be reset after the interval")
+
+	require.NoError(t, d.Close(context.Background()))
+	require.Equal(t, 1, next.count(sample.SummaryMessage), "summary should be logged on close")
+	summary := next.entries[len(next.entries)-1]
+	require.EqualValues(t, 87, summary.Attrs[0].Value.Uint64(), "summary should report the suppressed entries")
+	require.Zero(t, d.Suppressed(), "summarized entries should not be reported again")
+}
+
+func TestDriver_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	next := &recordingDriver{}
+	d := sample.NewDriver(next, sample.Options{Interval: time.Hour, Rate: 10, Burst: 5})
+	defer d.Close(context.Background())
+	start := time.Now()
+
+	logAt(d, context.Background(), start, 0, 20, "burst")
+	require.Equal(t, 5, next.count("burst"), "burst should be logged at once")
+
+	logAt(d, context.Background(), start.Add(time.Second), 10*time.Millisecond, 100, "burst")
+	// the bucket refills up to the burst in a second, then at 10 entries per second for one more second
+	require.InDelta(t, 5+5+10, next.count("burst"), 1)
+}
+
+func TestDriver_SampledTransaction(t *testing.T) {
+	t.Parallel()
+
+	next := &recordingDriver{}
+	tx := &transaction.Transaction{TraceID: "trace"}
+	tx.SetSampled(true)
+	ctx := tx.NewContext(context.Background())
+
+	d := sample.NewDriver(next, sample.Options{Interval: time.Hour, First: 1})
+	defer d.Close(context.Background())
+	logAt(d, ctx, time.Now(), time.Millisecond, 10, "limited")
+	require.Equal(t, 1, next.count("limited"), "sampled transactions should be limited by default")
+
+	d = sample.NewDriver(next, sample.Options{Interval: time.Hour, First: 1, KeepSampled: true})
+	defer d.Close(context.Background())
+	logAt(d, ctx, time.Now(), time.Millisecond, 10, "traced")
+	require.Equal(t, 10, next.count("traced"), "entries of sampled transactions should be kept when enabled")
+	require.Zero(t, d.Suppressed())
+}
+
+func TestDriver_PeriodicSummary(t *testing.T) {
+	t.Parallel()
+
+	next := &recordingDriver{}
+	d := sample.NewDriver(next, sample.Options{Interval: 20 * time.Millisecond, First: 1})
+	defer d.Close(context.Background())
+
+	logAt(d, context.Background(), time.Now(), 0, 5, "hot loop")
+	require.Eventually(t, func() bool {
+		return next.count(sample.SummaryMessage) == 1
+	}, time.Second, 10*time.Millisecond, "summary should be logged periodically")
+}
@@ -2,20 +2,33 @@
 package text
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/silvan-talos/tlp/logging"
 )
 
 const dateFormat = "2006-01-02 15:04:05.000"
 
+// lineBreaks escapes the line breaks of the message and attribute values, so every entry stays on a single line.
+var lineBreaks = strings.NewReplacer("\n", `\n`, "\r", `\r`)
+
+var bufPool = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// Driver writes every entry as a text line. It is safe for concurrent use: each line is formatted
+// separately and written to the output with a single call.
 type Driver struct {
-	writer *bufio.Writer
+	mu     sync.Mutex
+	output io.Writer
 }
 
 func NewDriver(output io.Writer) *Driver {
@@ -23,34 +36,113 @@ func NewDriver(output io.Writer) *Driver {
 		output = os.Stdout
 	}
 	return &Driver{
-		writer: bufio.NewWriter(output),
+		output: output,
 	}
 }
 
 func (d *Driver) Log(ctx context.Context, entry logging.Entry) {
-	// log format times - LEVEL: msg	traceID=123 details=[key1='value 1', composed-key='value 2'] transactionDetails=[userID='123', requestPath='/users/1/details']
-	_, _ = fmt.Fprintf(d.writer, "%s - %s: %s",
-		entry.Time.Format(dateFormat),
-		entry.Level,
-		entry.Message,
-	)
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer func() {
+		buf.Reset()
+		bufPool.Put(buf)
+	}()
+	// log format times - LEVEL: msg	logger=db.mysql source=/app/db.go:42 func=main.query traceID=123 spanID=456 details=[key1='value 1', composed-key='value 2'] transactionDetails=[userID='123', requestPath='/users/1/details']
+	entry.Attrs = logging.ResolveAttrs(entry.Attrs)
+	entry.TransactionAttrs = logging.ResolveAttrs(entry.TransactionAttrs)
+	// the line is built without fmt, so logging scalar attribute values does not allocate
+	buf.Write(entry.Time.AppendFormat(buf.AvailableBuffer(), dateFormat))
+	buf.WriteString(" - ")
+	buf.WriteString(entry.Level.String())
+	buf.WriteString(": ")
+	if strings.ContainsAny(entry.Message, "\r\n") {
+		_, _ = lineBreaks.WriteString(buf, entry.Message)
+	} else {
+		buf.WriteString(entry.Message)
+	}
+	sep := "\t"
+	if entry.Logger != "" {
+		buf.WriteString(sep)
+		buf.WriteString("logger=")
+		buf.WriteString(entry.Logger)
+		sep = " "
+	}
+	if entry.Source != nil {
+		buf.WriteString(sep)
+		buf.WriteString("source=")
+		writeLocation(buf, entry.Source)
+		buf.WriteString(" func=")
+		buf.WriteString(entry.Source.Function)
+		sep = " "
+	}
 	if entry.TraceID != "" {
-		_, _ = fmt.Fprintf(d.writer, "\ttraceID=%s", entry.TraceID)
+		buf.WriteString(sep)
+		buf.WriteString("traceID=")
+		buf.WriteString(entry.TraceID)
+	}
+	if entry.SpanID != "" {
+		buf.WriteString(" spanID=")
+		buf.WriteString(entry.SpanID)
 	}
 	if len(entry.Attrs) > 0 {
-		_, _ = fmt.Fprintf(d.writer, " details=[%s]", textFormatAttrs(entry.Attrs))
+		buf.WriteString(" details=[")
+		textFormatAttrs(buf, "", entry.Attrs, true)
+		buf.WriteByte(']')
 	}
 	if len(entry.TransactionAttrs) > 0 {
-		_, _ = fmt.Fprintf(d.writer, " transactionDetails=[%s]", textFormatAttrs(entry.TransactionAttrs))
+		buf.WriteString(" transactionDetails=[")
+		textFormatAttrs(buf, "", entry.TransactionAttrs, true)
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('\n')
+	// the stack trace follows the line as an indented block, like in the goroutine dumps
+	for _, frame := range entry.Stack {
+		buf.WriteByte('\t')
+		buf.WriteString(frame.Function)
+		buf.WriteString("\n\t\t")
+		writeLocation(buf, &frame)
+		buf.WriteByte('\n')
 	}
-	_ = d.writer.WriteByte('\n')
-	_ = d.writer.Flush()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = d.output.Write(buf.Bytes())
+}
+
+func writeLocation(buf *bytes.Buffer, source *logging.Source) {
+	buf.WriteString(source.File)
+	buf.WriteByte(':')
+	buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(source.Line), 10))
 }
 
-func textFormatAttrs(attrs []logging.Attr) string {
-	parts := make([]string, len(attrs))
-	for i, attr := range attrs {
-		parts[i] = fmt.Sprintf("%s='%v'", attr.Key, attr.Value)
+// textFormatAttrs writes the attrs separated by commas, the members of groups having their keys prefixed with the
+// group key and a dot. It reports whether the next attribute is the first one written.
+func textFormatAttrs(buf *bytes.Buffer, prefix string, attrs []logging.Attr, first bool) bool {
+	for _, attr := range attrs {
+		if attr.Value.Kind() == logging.KindGroup {
+			first = textFormatAttrs(buf, prefix+attr.Key+".", attr.Value.Group(), first)
+			continue
+		}
+		if !first {
+			buf.WriteString(", ")
+		}
+		first = false
+		buf.WriteString(prefix)
+		buf.WriteString(attr.Key)
+		buf.WriteString("='")
+		value := attr.Value
+		if value.Kind() == logging.KindAny {
+			if err, ok := value.Any().(error); ok {
+				// joined errors have multi-line messages
+				buf.WriteString(lineBreaks.Replace(logging.NewErrorDetails(err).String()))
+				buf.WriteByte('\'')
+				continue
+			}
+		}
+		if text := value.AppendText(buf.AvailableBuffer()); bytes.ContainsAny(text, "\r\n") {
+			buf.WriteString(lineBreaks.Replace(string(text)))
+		} else {
+			buf.Write(text)
+		}
+		buf.WriteByte('\'')
 	}
-	return strings.Join(parts, ", ")
+	return first
 }
@@ -0,0 +1,146 @@
+package text_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/log"
+	"github.com/silvan-talos/tlp/logging"
+	"github.com/silvan-talos/tlp/mock"
+	"github.com/silvan-talos/tlp/text"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+var lineRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3} - INFO: goroutine (\d+) entry (\d+)\ttraceID=test-trace ` +
+	`details=\[env='test', goroutine='(\d+)', entry='(\d+)'\] transactionDetails=\[name='test', type='race-test'\]$`)
+
+func TestDriver_ConcurrentLog(t *testing.T) {
+	t.Parallel()
+
+	const (
+		goroutines = 50
+		entries    = 100
+	)
+	var out bytes.Buffer
+	logger := log.NewLogger(text.NewDriver(&out), logging.LevelDebug).WithAttrs(logging.NewAttr("env", "test"))
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, ctx := tracer.StartTransaction(context.Background(), "test", "race-test")
+			defer tx.End()
+			for i := 0; i < entries; i++ {
+				logger.Info(ctx, fmt.Sprintf("goroutine %d entry %d", g, i), "goroutine", g, "entry", i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		match := lineRegexp.FindStringSubmatch(scanner.Text())
+		require.NotNil(t, match, "every line should be intact: %q", scanner.Text())
+		require.Equal(t, match[1], match[3], "entry attrs should not leak between goroutines")
+		require.Equal(t, match[2], match[4], "entry attrs should not leak between goroutines")
+		seen[match[1]+"-"+match[2]] = true
+	}
+	require.Len(t, seen, goroutines*entries, "every entry should be written exactly once")
+}
+
+func TestDriver_Log(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Level:   logging.LevelWarn,
+		Message: "slow query",
+		Logger:  "db.mysql",
+		Source:  &logging.Source{Function: "main.query", File: "/app/db.go", Line: 42},
+		TraceID: "test-trace",
+	})
+	require.Contains(t, out.String(), "WARN: slow query\tlogger=db.mysql source=/app/db.go:42 func=main.query traceID=test-trace\n")
+}
+
+func TestDriver_LogStack(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Level:   logging.LevelError,
+		Message: "update user",
+		Stack: []logging.Source{
+			{Function: "main.update", File: "/app/user.go", Line: 12},
+			{Function: "main.main", File: "/app/main.go", Line: 5},
+		},
+	})
+	require.Contains(t, out.String(), "ERROR: update user\n"+
+		"\tmain.update\n\t\t/app/user.go:12\n"+
+		"\tmain.main\n\t\t/app/main.go:5\n")
+}
+
+func TestDriver_LogError(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Message: "update user",
+		Attrs:   []logging.Attr{logging.NewAttr("err", fmt.Errorf("update user: %w", errors.New("bind failed")))},
+	})
+	require.Contains(t, out.String(), "details=[err='update user: bind failed (*fmt.wrapError) caused by [bind failed (*errors.errorString)]']")
+}
+
+func TestDriver_LogJoinedError(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Message: "cleanup",
+		Attrs: []logging.Attr{
+			logging.NewAttr("err", errors.Join(errors.New("first"), errors.New("second"))),
+			logging.String("query", "select 1\nfrom dual"),
+		},
+	})
+	require.Equal(t, 1, bytes.Count(out.Bytes(), []byte("\n")), "entry should be written on a single line")
+	require.Contains(t, out.String(), `err='first\nsecond (*errors.joinError)`)
+	require.Contains(t, out.String(), `query='select 1\nfrom dual'`)
+}
+
+func TestDriver_LogMultiLineMessage(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Level:   logging.LevelInfo,
+		Message: "query failed:\r\nselect 1",
+	})
+	require.Equal(t, 1, bytes.Count(out.Bytes(), []byte("\n")), "entry should be written on a single line")
+	require.Contains(t, out.String(), `INFO: query failed:\r\nselect 1`)
+}
+
+func TestDriver_LogValues(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	text.NewDriver(&out).Log(context.Background(), logging.Entry{
+		Message: "request completed",
+		Attrs: []logging.Attr{
+			logging.Int("status", 200),
+			logging.Duration("elapsed", 1500*time.Microsecond),
+			logging.Group("user", logging.Int("id", 12), logging.Bool("admin", false)),
+		},
+	})
+	require.Contains(t, out.String(), "details=[status='200', elapsed='1.5ms', user.id='12', user.admin='false']")
+}
@@ -0,0 +1,113 @@
+package transaction
+
+import (
+	"slices"
+	"time"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// Outcome tells whether a transaction succeeded or failed.
+type Outcome string
+
+const (
+	OutcomeUnknown Outcome = "unknown"
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Error is an error captured during a transaction. Its details, including the wrapped chain, are the ones logged
+// for error attributes.
+type Error struct {
+	Time time.Time
+	logging.ErrorDetails
+	// Err is the original error.
+	Err error `json:"-"`
+}
+
+// NewError captures the message, type, fields and wrapped chain of err.
+// Errors joined with errors.Join contribute all of their branches to the chain.
+func NewError(err error) Error {
+	return Error{
+		Time:         time.Now(),
+		ErrorDetails: logging.NewErrorDetails(err),
+		Err:          err,
+	}
+}
+
+// SetResult sets a short description of the transaction result, like "HTTP 2xx".
+// The result is also added to the transaction attrs.
+func (tx *Transaction) SetResult(result string) {
+	tx.stateMu.Lock()
+	defer tx.stateMu.Unlock()
+	tx.result = result
+	tx.setAttr("result", result)
+}
+
+func (tx *Transaction) Result() string {
+	tx.stateMu.Lock()
+	defer tx.stateMu.Unlock()
+	return tx.result
+}
+
+// SetOutcome marks the transaction as successful or failed.
+// The outcome is also added to the transaction attrs.
+func (tx *Transaction) SetOutcome(outcome Outcome) {
+	tx.stateMu.Lock()
+	defer tx.stateMu.Unlock()
+	tx.setOutcome(outcome)
+}
+
+func (tx *Transaction) setOutcome(outcome Outcome) {
+	tx.outcome = outcome
+	tx.setAttr("outcome", string(outcome))
+}
+
+// Outcome returns the transaction outcome, OutcomeUnknown if it was not set.
+func (tx *Transaction) Outcome() Outcome {
+	tx.stateMu.Lock()
+	defer tx.stateMu.Unlock()
+	if tx.outcome == "" {
+		return OutcomeUnknown
+	}
+	return tx.outcome
+}
+
+// RecordError captures err, forwards it to the recorder and marks the transaction as failed.
+// Nil errors are ignored.
+func (tx *Transaction) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	txErr := NewError(err)
+	tx.stateMu.Lock()
+	tx.errors = append(tx.errors, txErr)
+	tx.setOutcome(OutcomeFailure)
+	tx.stateMu.Unlock()
+	if tx.recorder != nil {
+		tx.recorder.RecordError(tx.ctx, tx, txErr)
+	}
+}
+
+// Errors returns a copy of the errors recorded during the transaction.
+func (tx *Transaction) Errors() []Error {
+	tx.stateMu.Lock()
+	defer tx.stateMu.Unlock()
+	return slices.Clone(tx.errors)
+}
+
+// setAttr replaces the value of the attribute with the given key or appends it if missing.
+// The attrs are copied, since entries being logged may still read the current ones.
+func (tx *Transaction) setAttr(key string, value string) {
+	tx.attrsMu.Lock()
+	defer tx.attrsMu.Unlock()
+	attrs := slices.Clone(tx.Attrs)
+	for i, attr := range attrs {
+		if attr.Key == key {
+			attrs[i].Value = logging.StringValue(value)
+			tx.Attrs = attrs
+			return
+		}
+	}
+	tx.Attrs = append(attrs, logging.String(key, value))
+}
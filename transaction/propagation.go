@@ -0,0 +1,130 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context header names, see https://www.w3.org/TR/trace-context/.
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+const (
+	traceParentVersion = "00"
+	flagSampled        = 0x01
+)
+
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+// TraceParent is the trace context received from a remote caller.
+type TraceParent struct {
+	TraceID  string
+	ParentID string
+	Sampled  bool
+	// State is the raw tracestate header value, passed along untouched.
+	State string
+}
+
+// ParseTraceParent decodes a `traceparent` header value.
+func ParseTraceParent(value string) (TraceParent, error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		return TraceParent{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, value)
+	}
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isHex(version, 2) || version == "ff",
+		version == traceParentVersion && len(parts) != 4,
+		!isHex(traceID, 32) || isZero(traceID),
+		!isHex(parentID, 16) || isZero(parentID),
+		!isHex(flags, 2):
+		return TraceParent{}, fmt.Errorf("%w: %q", ErrInvalidTraceParent, value)
+	}
+	f, _ := hex.DecodeString(flags)
+	return TraceParent{
+		TraceID:  traceID,
+		ParentID: parentID,
+		Sampled:  f[0]&flagSampled != 0,
+	}, nil
+}
+
+// String encodes the trace parent as a version 00 `traceparent` header value.
+func (tp TraceParent) String() string {
+	flags := "00"
+	if tp.Sampled {
+		flags = "01"
+	}
+	return strings.Join([]string{traceParentVersion, tp.TraceID, tp.ParentID, flags}, "-")
+}
+
+type traceParentKey struct{}
+
+// ContextWithTraceParent stores a remote trace parent in ctx. Recorders continue its trace on the next
+// StartTransaction call made with the returned context.
+func ContextWithTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, tp)
+}
+
+// TraceParentFromContext returns the remote trace parent stored in ctx, if any.
+func TraceParentFromContext(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentKey{}).(TraceParent)
+	return tp, ok
+}
+
+// Extract reads the `traceparent` and `tracestate` headers of an incoming request into ctx.
+// Invalid or missing headers leave ctx untouched, so a new trace gets started.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	tp, err := ParseTraceParent(header.Get(TraceParentHeader))
+	if err != nil {
+		return ctx
+	}
+	tp.State = strings.Join(header.Values(TraceStateHeader), ",")
+	return ContextWithTraceParent(ctx, tp)
+}
+
+// Inject writes the trace context of the transaction and innermost span in ctx to the headers of an
+// outgoing request. Nothing is written if the trace is not W3C compatible.
+func Inject(ctx context.Context, header http.Header) {
+	tx := FromContext(ctx)
+	parentID := tx.ID
+	if span := SpanFromContext(ctx); span.ID != "" {
+		parentID = span.ID
+	}
+	if !isHex(tx.TraceID, 32) || !isHex(parentID, 16) {
+		return
+	}
+	tp := TraceParent{
+		TraceID:  tx.TraceID,
+		ParentID: parentID,
+		Sampled:  tx.Sampled(),
+	}
+	header.Set(TraceParentHeader, tp.String())
+	if tx.TraceState != "" {
+		header.Set(TraceStateHeader, tx.TraceState)
+	} else {
+		header.Del(TraceStateHeader)
+	}
+}
+
+// isHex reports whether s is a lowercase hex string of the given length.
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,109 @@
+package transaction_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/dummy"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+const (
+	testTraceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	testParentID = "00f067aa0ba902b7"
+)
+
+func TestParseTraceParent(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value     string
+		want      transaction.TraceParent
+		shouldErr bool
+	}{
+		{"00-" + testTraceID + "-" + testParentID + "-01", transaction.TraceParent{TraceID: testTraceID, ParentID: testParentID, Sampled: true}, false},
+		{"00-" + testTraceID + "-" + testParentID + "-00", transaction.TraceParent{TraceID: testTraceID, ParentID: testParentID}, false},
+		{"01-" + testTraceID + "-" + testParentID + "-03-future", transaction.TraceParent{TraceID: testTraceID, ParentID: testParentID, Sampled: true}, false},
+		{"00-" + testTraceID + "-" + testParentID + "-01-extra", transaction.TraceParent{}, true},
+		{"ff-" + testTraceID + "-" + testParentID + "-01", transaction.TraceParent{}, true},
+		{"00-00000000000000000000000000000000-" + testParentID + "-01", transaction.TraceParent{}, true},
+		{"00-" + testTraceID + "-0000000000000000-01", transaction.TraceParent{}, true},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-" + testParentID + "-01", transaction.TraceParent{}, true},
+		{"00-" + testTraceID + "-" + testParentID, transaction.TraceParent{}, true},
+		{"", transaction.TraceParent{}, true},
+	} {
+		tp, err := transaction.ParseTraceParent(tc.value)
+		if tc.shouldErr {
+			require.ErrorIs(t, err, transaction.ErrInvalidTraceParent, "test case should return an error")
+		} else {
+			require.NoError(t, err, "test case should not return an error")
+		}
+		require.Equal(t, tc.want, tp, "trace parent should be parsed correctly")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("continue remote trace", continueRemoteTrace)
+	t.Run("start new trace on invalid header", startNewTraceOnInvalidHeader)
+}
+
+func continueRemoteTrace(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set(transaction.TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+	header.Set(transaction.TraceStateHeader, "vendor=value")
+	ctx := transaction.Extract(context.Background(), header)
+
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	tx, _ := tracer.StartTransaction(ctx, "test", "unit-test")
+	require.Equal(t, testTraceID, tx.TraceID, "remote trace should be continued")
+	require.Equal(t, testParentID, tx.ParentID, "remote parent should be recorded")
+	require.True(t, tx.Sampled(), "sampled flag should be propagated")
+	require.Equal(t, "vendor=value", tx.TraceState, "trace state should be propagated")
+	require.NotEqual(t, testParentID, tx.ID, "transaction should have its own ID")
+}
+
+func startNewTraceOnInvalidHeader(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	header.Set(transaction.TraceParentHeader, "invalid")
+	ctx := transaction.Extract(context.Background(), header)
+	_, ok := transaction.TraceParentFromContext(ctx)
+	require.False(t, ok, "invalid header should not be extracted")
+
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	tx, _ := tracer.StartTransaction(ctx, "test", "unit-test")
+	require.Len(t, tx.TraceID, 32, "a W3C compatible trace should be started")
+	require.Empty(t, tx.ParentID, "new trace should not have a parent")
+}
+
+func TestInject(t *testing.T) {
+	t.Parallel()
+
+	in := http.Header{}
+	in.Set(transaction.TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+	in.Set(transaction.TraceStateHeader, "vendor=value")
+	ctx := transaction.Extract(context.Background(), in)
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	tx, ctx := tracer.StartTransaction(ctx, "test", "unit-test")
+
+	out := http.Header{}
+	transaction.Inject(ctx, out)
+	require.Equal(t, "00-"+testTraceID+"-"+tx.ID+"-01", out.Get(transaction.TraceParentHeader),
+		"transaction should be the parent of the outgoing request")
+	require.Equal(t, "vendor=value", out.Get(transaction.TraceStateHeader), "trace state should be forwarded")
+
+	span, ctx := transaction.StartSpan(ctx, "call service", "http")
+	transaction.Inject(ctx, out)
+	require.Equal(t, "00-"+testTraceID+"-"+span.ID+"-01", out.Get(transaction.TraceParentHeader),
+		"innermost span should be the parent of the outgoing request")
+
+	empty := http.Header{}
+	transaction.Inject(context.Background(), empty)
+	require.Empty(t, empty, "nothing should be injected without a transaction")
+}
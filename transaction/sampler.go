@@ -0,0 +1,60 @@
+package transaction
+
+import (
+	"hash/fnv"
+	"math"
+	"strconv"
+)
+
+// Sampler decides whether a new trace is sampled. Traces continued from a remote parent keep its decision.
+type Sampler interface {
+	ShouldSample(traceID string) bool
+}
+
+// SamplerFunc adapts a function to the Sampler interface.
+type SamplerFunc func(traceID string) bool
+
+func (f SamplerFunc) ShouldSample(traceID string) bool {
+	return f(traceID)
+}
+
+// AlwaysSample samples every trace.
+func AlwaysSample() Sampler {
+	return SamplerFunc(func(string) bool { return true })
+}
+
+// NeverSample samples no trace.
+func NeverSample() Sampler {
+	return SamplerFunc(func(string) bool { return false })
+}
+
+// randomBits is the number of trailing trace ID bits used by RatioSampler, random in the W3C and uuid v4 IDs.
+const randomBits = 56
+
+// RatioSampler samples the given fraction of the traces. The decision is derived from the trace ID, so the services
+// sharing the same ratio agree on it.
+func RatioSampler(ratio float64) Sampler {
+	switch {
+	case ratio >= 1:
+		return AlwaysSample()
+	case ratio <= 0:
+		return NeverSample()
+	}
+	bound := uint64(ratio * (1 << randomBits))
+	return SamplerFunc(func(traceID string) bool {
+		return traceIDBits(traceID) < bound
+	})
+}
+
+// traceIDBits returns the trailing bits of a hex trace ID, or a hash of other trace IDs.
+func traceIDBits(traceID string) uint64 {
+	const hexDigits = randomBits / 4
+	if len(traceID) >= hexDigits {
+		if n, err := strconv.ParseUint(traceID[len(traceID)-hexDigits:], 16, 64); err == nil {
+			return n
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(traceID))
+	return h.Sum64() & (math.MaxUint64 >> (64 - randomBits))
+}
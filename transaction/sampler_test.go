@@ -0,0 +1,68 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/silvan-talos/tlp/dummy"
+	"github.com/silvan-talos/tlp/transaction"
+)
+
+func TestRatioSampler(t *testing.T) {
+	t.Parallel()
+
+	sampler := transaction.RatioSampler(0.25)
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	sampled := 0
+	for i := 0; i < 4000; i++ {
+		tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+		if sampler.ShouldSample(tx.TraceID) {
+			sampled++
+		}
+	}
+	require.InDelta(t, 1000, sampled, 150, "about a quarter of the traces should be sampled")
+	require.Equal(t, sampler.ShouldSample(testTraceID), sampler.ShouldSample(testTraceID), "decision should be stable")
+	require.True(t, transaction.RatioSampler(1).ShouldSample("not-hex"))
+	require.False(t, transaction.RatioSampler(0).ShouldSample(testTraceID))
+}
+
+func TestDummyRecorder_Sampling(t *testing.T) {
+	t.Run("sampler decides new traces", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := transaction.NewTracer(dummy.NewRecorder(dummy.WithSampler(transaction.NeverSample())))
+		tx, ctx := tracer.StartTransaction(context.Background(), "test", "unit-test")
+		require.False(t, tx.Sampled())
+		sampled, ok := transaction.SampledFromContext(ctx)
+		require.True(t, ok, "transaction should be found")
+		require.False(t, sampled)
+	})
+	t.Run("remote decision kept", func(t *testing.T) {
+		t.Parallel()
+
+		header := http.Header{}
+		header.Set(transaction.TraceParentHeader, "00-"+testTraceID+"-"+testParentID+"-01")
+		ctx := transaction.Extract(context.Background(), header)
+		tracer := transaction.NewTracer(dummy.NewRecorder(dummy.WithSampler(transaction.NeverSample())))
+		tx, _ := tracer.StartTransaction(ctx, "test", "unit-test")
+		require.True(t, tx.Sampled(), "continued traces should keep the parent decision")
+	})
+	t.Run("errors sampled", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := dummy.NewRecorder(dummy.WithSampler(transaction.NeverSample()), dummy.WithErrorSampling())
+		tx, _ := transaction.NewTracer(recorder).StartTransaction(context.Background(), "test", "unit-test")
+		tx.RecordError(errors.New("failed"))
+		require.True(t, tx.Sampled(), "transactions recording an error should be sampled")
+	})
+	t.Run("no transaction", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := transaction.SampledFromContext(context.Background())
+		require.False(t, ok)
+	})
+}
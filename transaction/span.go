@@ -0,0 +1,80 @@
+package transaction
+
+import (
+	"context"
+	"time"
+
+	"github.com/silvan-talos/tlp/logging"
+)
+
+// Span is a child operation of a Transaction (a DB query, an outgoing HTTP call etc.) with its own timing.
+// Spans can be nested by starting them from a context that already carries a span.
+type Span struct {
+	ID       string
+	ParentID string
+	TraceID  string
+	Name     string
+	Type     string
+	Attrs    []logging.Attr
+
+	recorder Recorder
+	// ctx is the context returned by the recorder, handed back to it when the span ends.
+	ctx      context.Context
+	start    time.Time
+	duration time.Duration
+}
+
+type spanKey struct{}
+
+// SpanFromContext returns the innermost span stored in ctx or an empty span if there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	// kept simple enough to be inlined, so the empty span does not escape in the callers reading its fields
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	if span == nil {
+		return &Span{}
+	}
+	return span
+}
+
+func (s *Span) NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// StartSpan starts a span as a child of the innermost span found in ctx, or of the transaction itself
+// if there is none.
+func (tx *Transaction) StartSpan(ctx context.Context, name, spanType string, attrs ...logging.Attr) (*Span, context.Context) {
+	parentID := tx.ID
+	if parent := SpanFromContext(ctx); parent.ID != "" {
+		parentID = parent.ID
+	}
+	span := &Span{}
+	if tx.recorder != nil {
+		span, ctx = tx.recorder.RecordSpan(ctx, tx, name, spanType)
+	}
+	span.ParentID = parentID
+	span.TraceID = tx.TraceID
+	span.Name = name
+	span.Type = spanType
+	span.Attrs = append(span.Attrs, attrs...)
+	span.recorder = tx.recorder
+	span.ctx = ctx
+	span.start = time.Now()
+	ctx = span.NewContext(ctx)
+	return span, ctx
+}
+
+// StartSpan starts a span inside the transaction carried by ctx.
+func StartSpan(ctx context.Context, name, spanType string, attrs ...logging.Attr) (*Span, context.Context) {
+	return FromContext(ctx).StartSpan(ctx, name, spanType, attrs...)
+}
+
+func (s *Span) End() {
+	s.duration = time.Now().Sub(s.start)
+	if s.recorder != nil {
+		s.recorder.EndSpan(s.ctx, s)
+	}
+}
+
+func (s *Span) GetDuration() time.Duration {
+	return s.duration
+}
@@ -3,6 +3,8 @@ package transaction
 
 import (
 	"context"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -13,10 +15,31 @@ var defaultTracer atomic.Pointer[Tracer]
 
 type Transaction struct {
 	TraceID string
-	Attrs   []logging.Attr
+	// ID identifies the transaction inside the trace and is the parent ID of its top-level spans.
+	ID string
+	// ParentID is the ID of the remote span that started the transaction, if the trace was continued.
+	ParentID string
+	// sampled is the W3C sampled flag, propagated to downstream services. It is read with Sampled and changed with
+	// SetSampled, as a recorder may sample the transaction while its entries are being logged.
+	sampled atomic.Bool
+	// TraceState is the vendor specific W3C tracestate, propagated untouched.
+	TraceState string
+	// Attrs are logged with every entry of the transaction. Once the transaction is started, they must be read with
+	// GetAttrs and changed with AddAttrs, which are safe for concurrent use.
+	Attrs []logging.Attr
+	// attrsMu guards the replacement of Attrs, which is never modified in place once the transaction started.
+	attrsMu sync.RWMutex
 
+	recorder Recorder
+	// ctx is the context returned by the recorder, handed back to it for further notifications.
+	ctx      context.Context
 	start    time.Time
 	duration time.Duration
+	// stateMu guards the result, outcome and errors, which may be set from several goroutines of the transaction.
+	stateMu sync.Mutex
+	result  string
+	outcome Outcome
+	errors  []Error
 }
 
 type transactionKey struct{}
@@ -29,12 +52,55 @@ func FromContext(ctx context.Context) *Transaction {
 	return tx
 }
 
+// SampledFromContext reports whether the trace of the transaction found in ctx is sampled, and whether ctx holds a
+// transaction at all. Unlike FromContext, it does not allocate.
+func SampledFromContext(ctx context.Context) (sampled, ok bool) {
+	tx, ok := ctx.Value(transactionKey{}).(*Transaction)
+	if !ok {
+		return false, false
+	}
+	return tx.sampled.Load(), true
+}
+
+// AttrsFromContext returns the attrs of the transaction found in ctx, nil if there is none. Unlike FromContext, it
+// does not allocate.
+func AttrsFromContext(ctx context.Context) []logging.Attr {
+	tx, ok := ctx.Value(transactionKey{}).(*Transaction)
+	if !ok {
+		return nil
+	}
+	return tx.GetAttrs()
+}
+
+// Sampled reports whether the trace of the transaction is sampled. It is safe for concurrent use.
+func (tx *Transaction) Sampled() bool {
+	return tx.sampled.Load()
+}
+
+// SetSampled changes the sampling decision of the transaction, e.g. when it records an error. It is safe for
+// concurrent use.
+func (tx *Transaction) SetSampled(sampled bool) {
+	tx.sampled.Store(sampled)
+}
+
+// NewContext stores the transaction in ctx. Spans of a previous transaction found in ctx are discarded.
 func (tx *Transaction) NewContext(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, spanKey{}, (*Span)(nil))
 	return context.WithValue(ctx, transactionKey{}, tx)
 }
 
+// Recorder records transactions and spans. RecordTransaction must continue the remote trace found
+// with TraceParentFromContext, if any.
 type Recorder interface {
 	RecordTransaction(ctx context.Context, name, transactionType string) (*Transaction, context.Context)
+	// EndTransaction is called when tx ends, using the context returned by RecordTransaction.
+	EndTransaction(ctx context.Context, tx *Transaction)
+	// RecordSpan starts recording a span of tx. The parent span, if any, is available in ctx.
+	RecordSpan(ctx context.Context, tx *Transaction, name, spanType string) (*Span, context.Context)
+	// EndSpan is called when the span ends, using the context returned by RecordSpan.
+	EndSpan(ctx context.Context, span *Span)
+	// RecordError is called for every error recorded on tx, using the context returned by RecordTransaction.
+	RecordError(ctx context.Context, tx *Transaction, txErr Error)
 }
 
 type Tracer struct {
@@ -56,6 +122,8 @@ func NewTracer(recorder Recorder) *Tracer {
 func (t *Tracer) StartTransaction(ctx context.Context, name, transactionType string, attrs ...logging.Attr) (*Transaction, context.Context) {
 	tx, ctx := t.recorder.RecordTransaction(ctx, name, transactionType)
 	tx.Attrs = append(tx.Attrs, attrs...)
+	tx.recorder = t.recorder
+	tx.ctx = ctx
 	tx.start = time.Now()
 	ctx = tx.NewContext(ctx)
 	return tx, ctx
@@ -63,6 +131,23 @@ func (t *Tracer) StartTransaction(ctx context.Context, name, transactionType str
 
 func (tx *Transaction) End() {
 	tx.duration = time.Now().Sub(tx.start)
+	if tx.recorder != nil {
+		tx.recorder.EndTransaction(tx.ctx, tx)
+	}
+}
+
+// GetAttrs returns the transaction attrs. The returned slice must not be modified.
+func (tx *Transaction) GetAttrs() []logging.Attr {
+	tx.attrsMu.RLock()
+	defer tx.attrsMu.RUnlock()
+	return tx.Attrs
+}
+
+// AddAttrs appends attrs to the transaction attrs. The slice is copied, so the entries being logged keep theirs.
+func (tx *Transaction) AddAttrs(attrs ...logging.Attr) {
+	tx.attrsMu.Lock()
+	defer tx.attrsMu.Unlock()
+	tx.Attrs = append(slices.Clip(tx.Attrs), attrs...)
 }
 
 func (tx *Transaction) GetDuration() time.Duration {
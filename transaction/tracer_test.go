@@ -2,10 +2,14 @@ package transaction_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/silvan-talos/tlp/dummy"
 	"github.com/silvan-talos/tlp/logging"
 	"github.com/silvan-talos/tlp/mock"
 	"github.com/silvan-talos/tlp/transaction"
@@ -18,7 +22,13 @@ func TestTracer_StartTransaction(t *testing.T) {
 func startTransactionSuccessfully(t *testing.T) {
 	t.Parallel()
 
-	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	ended := false
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{
+		EndTransactionFn: func(ctx context.Context, tx *transaction.Transaction) {
+			require.Equal(t, "test", ctx.Value("env"), "recorder context should be passed")
+			ended = true
+		},
+	})
 	tx, ctx := tracer.StartTransaction(context.Background(), "test", "unit-test", logging.NewAttr("env", "test"))
 	require.NotNil(t, tx, "expected transaction to be not nil")
 	require.Contains(t, tx.Attrs, logging.NewAttr("env", "test"), "returned transaction should contain the custom attrs")
@@ -26,6 +36,7 @@ func startTransactionSuccessfully(t *testing.T) {
 	require.Equal(t, tx, ctxTx, "retrieved transaction should be the same")
 	tx.End()
 	require.NotEmpty(t, tx.GetDuration(), "duration should be different from 0")
+	require.True(t, ended, "recorder should be notified when the transaction ends")
 }
 
 func TestFromContext(t *testing.T) {
@@ -49,3 +60,175 @@ func retrieveWhenNoneInContext(t *testing.T) {
 	ctxTx := transaction.FromContext(context.Background())
 	require.NotNil(t, ctxTx, "transaction must not be nil")
 }
+
+func TestTransaction_StartSpan(t *testing.T) {
+	t.Run("top-level span", startTopLevelSpan)
+	t.Run("nested spans", startNestedSpans)
+	t.Run("span without transaction", startSpanWithoutTransaction)
+}
+
+func startTopLevelSpan(t *testing.T) {
+	t.Parallel()
+
+	recorder := &mock.TransactionRecorder{}
+	ended := false
+	recorder.EndSpanFn = func(ctx context.Context, span *transaction.Span) {
+		ended = true
+	}
+	tracer := transaction.NewTracer(recorder)
+	tx, ctx := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	span, ctx := transaction.StartSpan(ctx, "select user", "db", logging.NewAttr("table", "users"))
+	require.Equal(t, "test-span-1", span.ID, "span ID should be set by the recorder")
+	require.Equal(t, tx.ID, span.ParentID, "top-level span should be a child of the transaction")
+	require.Equal(t, tx.TraceID, span.TraceID, "span should be part of the transaction trace")
+	require.Equal(t, "select user", span.Name)
+	require.Equal(t, "db", span.Type)
+	require.Contains(t, span.Attrs, logging.NewAttr("table", "users"), "span should contain the custom attrs")
+	require.Equal(t, span, transaction.SpanFromContext(ctx), "retrieved span should be the same")
+	span.End()
+	require.True(t, ended, "recorder should be notified when the span ends")
+	require.NotEmpty(t, span.GetDuration(), "duration should be different from 0")
+}
+
+func startNestedSpans(t *testing.T) {
+	t.Parallel()
+
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	_, ctx := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	parent, parentCtx := transaction.StartSpan(ctx, "handle", "app")
+	child, childCtx := transaction.StartSpan(parentCtx, "query", "db")
+	require.Equal(t, parent.ID, child.ParentID, "nested span should be a child of the outer span")
+	require.Equal(t, child, transaction.SpanFromContext(childCtx), "child context should carry the child span")
+	require.Equal(t, parent, transaction.SpanFromContext(parentCtx), "parent context should not be affected")
+
+	_, newTxCtx := tracer.StartTransaction(childCtx, "nested", "unit-test")
+	require.Empty(t, transaction.SpanFromContext(newTxCtx).ID, "a new transaction should not inherit spans")
+}
+
+func startSpanWithoutTransaction(t *testing.T) {
+	t.Parallel()
+
+	span, ctx := transaction.StartSpan(context.Background(), "orphan", "db")
+	require.NotNil(t, span, "span must not be nil")
+	require.Empty(t, span.ID, "span without a recorder should not have an ID")
+	require.Equal(t, span, transaction.SpanFromContext(ctx), "retrieved span should be the same")
+	span.End()
+}
+
+func TestTransaction_RecordError(t *testing.T) {
+	t.Run("capture error chain", captureErrorChain)
+	t.Run("capture joined errors", captureJoinedErrors)
+	t.Run("outcome and result", outcomeAndResult)
+}
+
+type notFoundError struct {
+	resource string
+}
+
+func (e *notFoundError) Error() string {
+	return e.resource + " not found"
+}
+
+func captureErrorChain(t *testing.T) {
+	t.Parallel()
+
+	var recorded []transaction.Error
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{
+		RecordErrorFn: func(ctx context.Context, tx *transaction.Transaction, txErr transaction.Error) {
+			require.Equal(t, "test", ctx.Value("env"), "recorder context should be passed")
+			recorded = append(recorded, txErr)
+		},
+	})
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	require.Equal(t, transaction.OutcomeUnknown, tx.Outcome(), "outcome should be unknown by default")
+	tx.RecordError(nil)
+	require.Empty(t, tx.Errors(), "nil errors should be ignored")
+
+	cause := &notFoundError{resource: "user"}
+	err := fmt.Errorf("get user: %w", cause)
+	tx.RecordError(err)
+	require.Len(t, recorded, 1, "error should be forwarded to the recorder")
+	require.Equal(t, tx.Errors(), recorded)
+	txErr := tx.Errors()[0]
+	require.Equal(t, "get user: user not found", txErr.Message)
+	require.Equal(t, "*fmt.wrapError", txErr.Type)
+	require.Equal(t, err, txErr.Err, "original error should be kept")
+	require.Len(t, txErr.Causes, 1, "wrapped chain should be captured")
+	require.Equal(t, "user not found", txErr.Causes[0].Message)
+	require.Equal(t, "*transaction_test.notFoundError", txErr.Causes[0].Type)
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome(), "transaction should be marked as failed")
+	require.Contains(t, tx.Attrs, logging.NewAttr("outcome", "failure"), "outcome should be part of the attrs")
+}
+
+func captureJoinedErrors(t *testing.T) {
+	t.Parallel()
+
+	err := errors.Join(errors.New("first"), fmt.Errorf("second: %w", errors.New("cause")))
+	txErr := transaction.NewError(err)
+	require.Equal(t, logging.NewErrorDetails(err), txErr.ErrorDetails, "errors should be described like in the logs")
+	require.Len(t, txErr.Causes, 2, "all branches should be captured")
+	require.Equal(t, "second: cause", txErr.Causes[1].Message)
+	require.Equal(t, "cause", txErr.Causes[1].Causes[0].Message, "wrapped chain of each branch should be captured")
+}
+
+func outcomeAndResult(t *testing.T) {
+	t.Parallel()
+
+	tracer := transaction.NewTracer(&mock.TransactionRecorder{})
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	tx.SetResult("HTTP 2xx")
+	tx.SetOutcome(transaction.OutcomeFailure)
+	tx.SetOutcome(transaction.OutcomeSuccess)
+	require.Equal(t, "HTTP 2xx", tx.Result())
+	require.Equal(t, transaction.OutcomeSuccess, tx.Outcome())
+	require.Contains(t, tx.Attrs, logging.NewAttr("result", "HTTP 2xx"))
+	require.Contains(t, tx.Attrs, logging.NewAttr("outcome", "success"))
+	require.NotContains(t, tx.Attrs, logging.NewAttr("outcome", "failure"), "outcome should be replaced")
+}
+
+func TestTransaction_AttrsConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test", logging.String("env", "test"))
+	before := tx.GetAttrs()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			for _, attr := range tx.GetAttrs() {
+				_ = attr.Value.String()
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		tx.SetResult(fmt.Sprintf("HTTP %d", i))
+		tx.SetOutcome(transaction.OutcomeSuccess)
+		tx.AddAttrs(logging.Int("i", i))
+	}
+	<-done
+	require.Len(t, before, 1, "attrs read before the changes should not be modified")
+	require.Equal(t, "HTTP 99", tx.Result())
+}
+
+func TestTransaction_RecordErrorConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	tracer := transaction.NewTracer(dummy.NewRecorder())
+	tx, _ := tracer.StartTransaction(context.Background(), "test", "unit-test")
+	var wg sync.WaitGroup
+	for g := 0; g < 2; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				tx.RecordError(fmt.Errorf("goroutine %d error %d", g, i))
+				_ = tx.Outcome()
+				_ = tx.Errors()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, tx.Errors(), 100, "every error should be recorded")
+	require.Equal(t, transaction.OutcomeFailure, tx.Outcome())
+}